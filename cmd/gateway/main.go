@@ -18,6 +18,8 @@ var cfg = utils.NewConfigMapWithValues(map[string]string{
     "neo4j_username": "neo4j",
     "neo4j_password": "development",
     "jwt_secret": "secret",
+    "module_lease_seconds": "30",
+    "module_registration_secret": "",
 })
 
 func runService() {
@@ -51,8 +53,21 @@ func main() {
         neo4jPort, cfg.Get("neo4j_username"), cfg.Get("neo4j_password"))
     defer persistence.Driver.Close()
 
+    // retrieve duration of module leases and parse
+    leaseSeconds, err := strconv.Atoi(cfg.Get("module_lease_seconds"))
+    if err != nil || leaseSeconds <= 0 {
+        panic(fmt.Errorf("invalid lease %s", cfg.Get("module_lease_seconds")))
+    }
+
+    // retrieve secret shared with modules registering with gateway
+    registrationSecret := cfg.Get("module_registration_secret")
+    if len(registrationSecret) == 0 {
+        panic(fmt.Errorf("missing module registration secret"))
+    }
+
     // generate new admin API and run on admin port
-    admin := gateway.NewGatewayAdminAPI(cfg.Get("jwt_secret"), 180)
+    admin := gateway.NewGatewayAdminAPI(cfg.Get("jwt_secret"), registrationSecret,
+        180, leaseSeconds)
     go func() {
         defer func() {
             if r := recover(); r != nil {
@@ -2,7 +2,9 @@ package main
 
 import (
     "fmt"
+    "time"
     "strconv"
+    _ "time/tzdata"
     
     "github.com/PSauerborn/lifelink/pkg/habits"
     "github.com/PSauerborn/lifelink/pkg/utils"
@@ -15,8 +17,35 @@ var cfg = utils.NewConfigMapWithValues(map[string]string{
     "neo4j_port": "7687",
     "neo4j_username": "neo4j",
     "neo4j_password": "development",
+    "trash_retention_days": "30",
+    "trash_purge_interval_minutes": "60",
+    "reminder_interval_seconds": "60",
+    "reminder_sink": "log",
+    "reminder_sink_target": "",
+    "reconcile_hour_utc": "3",
+    "gateway_admin_url": "http://lifelink-gateway:8081",
+    "module_registration_secret": "",
+    "module_name": "habits",
+    "module_redirect": "http://lifelink-habits-api:10865",
+    "module_description": "Habit tracking, challenges and goals",
+    "todo_api_host": "lifelink-todo-api",
+    "todo_api_port": "10865",
 })
 
+// funcion used to retrieve downstream microservice
+// configuration/connection settings for TODO API
+func getTodoAPIConfig() utils.APIDependencyConfig {
+    apiPort, err := strconv.Atoi(cfg.Get("todo_api_port"))
+    if err != nil {
+        panic("received invalid api port for TODO API")
+    }
+    return utils.APIDependencyConfig{
+        Host: cfg.Get("todo_api_host"),
+        Port: &apiPort,
+        Protocol: "http",
+    }
+}
+
 func main() {
     // configure log level
     cfg.ConfigureLogging()
@@ -37,6 +66,47 @@ func main() {
     persistence := habits.SetGraphPersistence(cfg.Get("neo4j_host"), 
         neo4jPort, cfg.Get("neo4j_username"), cfg.Get("neo4j_password"))
     defer persistence.Driver.Close()
+
+    // retrieve trash retention settings and start purging
+    // expired habits from the trash in the background
+    retentionDays, err := strconv.Atoi(cfg.Get("trash_retention_days"))
+    if err != nil {
+        panic(fmt.Errorf("invalid retention %s", cfg.Get("trash_retention_days")))
+    }
+    purgeInterval, err := strconv.Atoi(cfg.Get("trash_purge_interval_minutes"))
+    if err != nil {
+        panic(fmt.Errorf("invalid interval %s", cfg.Get("trash_purge_interval_minutes")))
+    }
+    go habits.RunTrashPurger(time.Duration(purgeInterval) * time.Minute,
+        time.Duration(retentionDays) * 24 * time.Hour)
+
+    // generate notifier used to deliver reminders and
+    // start evaluating habit reminders in the background
+    reminderInterval, err := strconv.Atoi(cfg.Get("reminder_interval_seconds"))
+    if err != nil {
+        panic(fmt.Errorf("invalid interval %s", cfg.Get("reminder_interval_seconds")))
+    }
+    notifier, err := utils.NewNotifier(cfg.Get("reminder_sink"), cfg.Get("reminder_sink_target"))
+    if err != nil {
+        panic(fmt.Errorf("unable to generate reminder notifier: %+v", err))
+    }
+    go habits.RunReminderScheduler(time.Duration(reminderInterval) * time.Second, notifier)
+
+    // schedule nightly reconciliation of habit streaks
+    reconcileHour, err := strconv.Atoi(cfg.Get("reconcile_hour_utc"))
+    if err != nil || reconcileHour < 0 || reconcileHour > 23 {
+        panic(fmt.Errorf("invalid hour %s", cfg.Get("reconcile_hour_utc")))
+    }
+    go habits.RunStreakReconciler(reconcileHour)
+
+    // register service as gateway module and keep module lease alive
+    go utils.NewGatewayRegistrar(cfg.Get("gateway_admin_url"),
+        cfg.Get("module_registration_secret"), utils.ModuleRegistration{
+        ModuleName: cfg.Get("module_name"),
+        ModuleRedirect: cfg.Get("module_redirect"),
+        ModuleDescription: cfg.Get("module_description"),
+    }).Run()
+
     // generate new instance of API and run
-    habits.NewHabitsAPI().Run(fmt.Sprintf(":%d", listenPort))
+    habits.NewHabitsAPI(getTodoAPIConfig()).Run(fmt.Sprintf(":%d", listenPort))
 }
\ No newline at end of file
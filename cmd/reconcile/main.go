@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/habits"
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+var cfg = utils.NewConfigMapWithValues(map[string]string{
+	"log_level":      "INFO",
+	"neo4j_host":     "localhost",
+	"neo4j_port":     "7687",
+	"neo4j_username": "neo4j",
+	"neo4j_password": "development",
+})
+
+// one-shot command used to reconcile habit streaks
+// outside of the nightly schedule
+func main() {
+	// configure log level
+	cfg.ConfigureLogging()
+
+	// retrieve port for neo4j and parse to
+	neo4jPort, err := strconv.Atoi(cfg.Get("neo4j_port"))
+	if err != nil {
+		panic(fmt.Errorf("invalid port %s", cfg.Get("neo4j_port")))
+	}
+
+	// set new graph peristence layer and defer closing
+	persistence := habits.SetGraphPersistence(cfg.Get("neo4j_host"),
+		neo4jPort, cfg.Get("neo4j_username"), cfg.Get("neo4j_password"))
+	defer persistence.Driver.Close()
+
+	corrected, err := habits.ReconcileHabitStreaks()
+	if err != nil {
+		panic(fmt.Errorf("unable to reconcile habit streaks: %+v", err))
+	}
+	log.Info(fmt.Sprintf("successfully reconciled habits: corrected %d habit(s)", corrected))
+}
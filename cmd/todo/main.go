@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/PSauerborn/lifelink/pkg/todo"
 	"github.com/PSauerborn/lifelink/pkg/utils"
@@ -15,6 +16,21 @@ var cfg = utils.NewConfigMapWithValues(map[string]string{
 	"neo4j_port":     "7687",
 	"neo4j_username": "neo4j",
 	"neo4j_password": "development",
+
+	"gateway_admin_url":          "http://lifelink-gateway:8081",
+	"module_registration_secret": "",
+	"module_name":                "todo",
+	"module_redirect":            "http://lifelink-todo-api:10865",
+	"module_description":         "TODO items, projects and time tracking",
+
+	"reminder_interval_seconds": "60",
+	"reminder_sink":             "log",
+	"reminder_sink_target":      "",
+
+	"blob_store":                "local",
+	"blob_store_target":         "/var/lib/lifelink/attachments",
+	"attachment_max_file_bytes": "10485760",
+	"attachment_quota_bytes":    "104857600",
 })
 
 func main() {
@@ -37,6 +53,43 @@ func main() {
 	persistence := todo.SetGraphPersistence(cfg.Get("neo4j_host"),
 		neo4jPort, cfg.Get("neo4j_username"), cfg.Get("neo4j_password"))
 	defer persistence.Driver.Close()
+
+	// register service as gateway module so that requests are
+	// proxied via /api/todo/... and keep module lease alive
+	go utils.NewGatewayRegistrar(cfg.Get("gateway_admin_url"),
+		cfg.Get("module_registration_secret"), utils.ModuleRegistration{
+			ModuleName:        cfg.Get("module_name"),
+			ModuleRedirect:    cfg.Get("module_redirect"),
+			ModuleDescription: cfg.Get("module_description"),
+		}).Run()
+
+	// generate notifier used to deliver reminders and start
+	// delivering TODO reminders in the background
+	reminderInterval, err := strconv.Atoi(cfg.Get("reminder_interval_seconds"))
+	if err != nil {
+		panic(fmt.Errorf("invalid interval %s", cfg.Get("reminder_interval_seconds")))
+	}
+	notifier, err := utils.NewNotifier(cfg.Get("reminder_sink"), cfg.Get("reminder_sink_target"))
+	if err != nil {
+		panic(fmt.Errorf("unable to generate reminder notifier: %+v", err))
+	}
+	go todo.RunReminderWorker(time.Duration(reminderInterval)*time.Second, notifier)
+
+	// generate blob store used to store attachments and set limits
+	maxFileBytes, err := strconv.ParseInt(cfg.Get("attachment_max_file_bytes"), 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("invalid size %s", cfg.Get("attachment_max_file_bytes")))
+	}
+	quotaBytes, err := strconv.ParseInt(cfg.Get("attachment_quota_bytes"), 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("invalid size %s", cfg.Get("attachment_quota_bytes")))
+	}
+	blobs, err := utils.NewBlobStore(cfg.Get("blob_store"), cfg.Get("blob_store_target"))
+	if err != nil {
+		panic(fmt.Errorf("unable to generate blob store: %+v", err))
+	}
+	todo.SetBlobStore(blobs, maxFileBytes, quotaBytes)
+
 	// generate new instance of API and run
 	todo.NewTodoAPI().Run(fmt.Sprintf(":%d", listenPort))
 }
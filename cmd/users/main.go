@@ -15,6 +15,11 @@ var cfg = utils.NewConfigMapWithValues(map[string]string{
     "neo4j_port": "7687",
     "neo4j_username": "neo4j",
     "neo4j_password": "development",
+    "gateway_admin_url": "http://lifelink-gateway:8081",
+    "module_registration_secret": "",
+    "module_name": "users",
+    "module_redirect": "http://lifelink-users-api:10866",
+    "module_description": "User accounts and details",
 })
 
 func main() {
@@ -37,6 +42,15 @@ func main() {
     persistence := users.SetGraphPersistence(cfg.Get("neo4j_host"), 
         neo4jPort, cfg.Get("neo4j_username"), cfg.Get("neo4j_password"))
     defer persistence.Driver.Close()
+
+    // register service as gateway module and keep module lease alive
+    go utils.NewGatewayRegistrar(cfg.Get("gateway_admin_url"),
+        cfg.Get("module_registration_secret"), utils.ModuleRegistration{
+        ModuleName: cfg.Get("module_name"),
+        ModuleRedirect: cfg.Get("module_redirect"),
+        ModuleDescription: cfg.Get("module_description"),
+    }).Run()
+
     // generate new instance of API and run
     users.NewUsersAPI().Run(fmt.Sprintf(":%d", listenPort))
 }
\ No newline at end of file
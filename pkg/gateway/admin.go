@@ -3,6 +3,7 @@ package gateway
 import (
     "fmt"
     "time"
+    "strings"
     "net/http"
 
     "github.com/dgrijalva/jwt-go"
@@ -13,16 +14,25 @@ import (
 var (
     jwtSecret string
     tokenExpiryMinutes int
+    moduleLeaseSeconds int
 )
 
-// function used to generate new API gateway admin service
-func NewGatewayAdminAPI(secret string, tokenExpiry int) *gin.Engine {
+// function used to generate new API gateway admin service. modules
+// must present the registration secret to register or renew leases,
+// and modules can only be listed with an admin token
+func NewGatewayAdminAPI(secret, registrationSecret string, tokenExpiry, leaseSeconds int) *gin.Engine {
     // set variables to be used globally
     jwtSecret, tokenExpiryMinutes = secret, tokenExpiry
+    moduleLeaseSeconds = leaseSeconds
 
     router := gin.Default()
     router.GET("/admin/health_check", healthCheckHandler)
     router.POST("/admin/token", getTokenHandler)
+
+    router.GET("/admin/modules", JWTMiddleware(secret, true), getModulesHandler)
+    registration := ModuleRegistrationMiddleware(registrationSecret)
+    router.POST("/admin/modules", registration, registerModuleHandler)
+    router.PUT("/admin/modules/:moduleName/lease", registration, renewModuleLeaseHandler)
     return router
 }
 
@@ -73,4 +83,85 @@ func getTokenHandler(ctx *gin.Context) {
     }
     ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
         "success": true, "token": token})
-}
\ No newline at end of file
+}
+
+// function used to evaluate the expiry time of a new module lease
+func getLeaseExpiry() time.Time {
+    return time.Now().UTC().Add(time.Duration(moduleLeaseSeconds) * time.Second)
+}
+
+// API handler used to retrieve all modules along with
+// their availability based on their leases
+func getModulesHandler(ctx *gin.Context) {
+    log.Info("received request to retrieve modules")
+    modules, err := persistence.GetModules()
+    if err != nil {
+        log.Error(fmt.Errorf("unable to retrieve modules: %+v", err))
+        ctx.JSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
+            "success": false, "message": "Internal server error"})
+        return
+    }
+    now := time.Now().UTC()
+    results := []gin.H{}
+    for _, module := range modules {
+        results = append(results, gin.H{"module": module, "available": module.Available(now)})
+    }
+    ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+        "success": true, "modules": results})
+}
+
+// API handler used by modules to register themselves with the
+// gateway. registered modules receive a lease that must be renewed
+// before it expires, else requests to the module are rejected
+func registerModuleHandler(ctx *gin.Context) {
+    log.Info("received request to register module")
+    var request struct {
+        ModuleName        string `json:"module_name"        binding:"required"`
+        ModuleRedirect    string `json:"module_redirect"    binding:"required"`
+        ModuleDescription string `json:"module_description" binding:"required"`
+        TrimAppName       bool   `json:"trim_app_name"`
+    }
+    // parse request body from context
+    if err := ctx.ShouldBind(&request); err != nil {
+        log.Error(fmt.Errorf("received invalid request body: %+v", err))
+        ctx.JSON(http.StatusBadRequest, gin.H{
+            "http_code": http.StatusBadRequest, "message": "Invalid request body"})
+        return
+    }
+
+    expires := getLeaseExpiry()
+    module := Module{
+        ModuleName: strings.ToLower(request.ModuleName),
+        ModuleRedirect: request.ModuleRedirect,
+        ModuleDescription: request.ModuleDescription,
+        TrimAppName: request.TrimAppName,
+    }
+    if err := persistence.RegisterModule(module, expires); err != nil {
+        log.Error(fmt.Errorf("unable to register module: %+v", err))
+        ctx.JSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
+            "success": false, "message": "Internal server error"})
+        return
+    }
+    ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK, "success": true,
+        "lease_expires": expires, "lease_seconds": moduleLeaseSeconds})
+}
+
+// API handler used by modules to renew their lease
+func renewModuleLeaseHandler(ctx *gin.Context) {
+    log.Info("received request to renew module lease")
+    expires := getLeaseExpiry()
+    if err := persistence.RenewModuleLease(strings.ToLower(ctx.Param("moduleName")), expires); err != nil {
+        log.Error(fmt.Errorf("unable to renew module lease: %+v", err))
+        switch err {
+        case ErrInvalidModule:
+            ctx.JSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+                "success": false, "message": "Module does not exist"})
+        default:
+            ctx.JSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
+                "success": false, "message": "Internal server error"})
+        }
+        return
+    }
+    ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK, "success": true,
+        "lease_expires": expires, "lease_seconds": moduleLeaseSeconds})
+}
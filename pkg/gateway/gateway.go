@@ -2,6 +2,7 @@ package gateway
 
 import (
     "fmt"
+    "time"
     "strings"
     "net/http"
     "net/url"
@@ -13,6 +14,12 @@ import (
 
 var persistence *GraphPersistence
 
+// define function used to retrieve module details from the
+// graph persistence. note that the function is replaced in tests
+var getModuleDetails = func(name string) (Module, error) {
+    return persistence.GetModuleDetails(name)
+}
+
 // function used to set new instance of graph persistence
 // for global variables to use
 func SetGraphPersistence(host string, port int,
@@ -42,13 +49,16 @@ func proxyHandler(ctx *gin.Context) {
     log.Debug(fmt.Sprintf("proxying request for user %s", uid))
     // inject user ID into downstream headers
     ctx.Request.Header.Set("X-Authenticated-Userid", uid)
-    // get module from graph persistence and handle errors
-    module, err := persistence.GetModuleDetails(ctx.Param("application"))
+    // get module from graph persistence and handle errors. module
+    // names are registered in lowercase, so that legacy clients using
+    // uppercase names (e.g. /api/TODO/...) still reach the module
+    moduleName := strings.ToLower(ctx.Param("application"))
+    module, err := getModuleDetails(moduleName)
     if err != nil {
         switch err {
         case ErrInvalidModule:
             log.Error(fmt.Sprintf("unable to retrieve module details: invalid module %s",
-                ctx.Param("application")))
+                moduleName))
             ctx.AbortWithStatusJSON(http.StatusBadGateway, gin.H{
                 "http_code": http.StatusBadGateway, "success": false,
                 "message": "Bad Gateway"})
@@ -60,6 +70,15 @@ func proxyHandler(ctx *gin.Context) {
         }
         return
     }
+    // return unavailable if module has not renewed its lease
+    if !module.Available(time.Now().UTC()) {
+        log.Error(fmt.Sprintf("unable to proxy request: lease for module %s expired at %s",
+            module.ModuleName, module.LeaseExpires))
+        ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+            "http_code": http.StatusServiceUnavailable, "success": false,
+            "message": "Service unavailable"})
+        return
+    }
     // proxy request to relevant microservices
     proxyRequest(module, ctx.Writer, ctx.Request)
 }
@@ -85,8 +104,10 @@ func proxyRequest(app Module, response http.ResponseWriter, request *http.Reques
         redirectUrl = app.ModuleRedirect
     }
 
-    // remove /api segment from request path
+    // remove /api segment from request path and forward
+    // prefix so that modules can build redirect locations
     request.URL.Path = strings.Replace(request.URL.Path, "/api", "", -1)
+    request.Header.Set("X-Forwarded-Prefix", "/api")
     log.Info(fmt.Sprintf("proxying request to %s", redirectUrl))
     // construct new URL, set proxy headers and proxy
     redirect, _ := url.Parse(redirectUrl)
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+    "testing"
+    "net/http"
+    "net/http/httptest"
+
+    "github.com/dgrijalva/jwt-go"
+    "github.com/gin-gonic/gin"
+
+    "github.com/PSauerborn/lifelink/pkg/todo"
+)
+
+// function used to generate a signed access token for tests
+func newTestToken(t *testing.T, secret string) string {
+    token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"uid": "test-user"})
+    signed, err := token.SignedString([]byte(secret))
+    if err != nil {
+        t.Fatalf("unable to sign token: %+v", err)
+    }
+    return signed
+}
+
+// requests from legacy clients using the uppercase /api/TODO prefix
+// must reach the todo module and be redirected to the lowercase
+// routes without losing the /api prefix of the gateway
+func TestLegacyTodoPrefixIsRedirected(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    // run todo API as downstream module
+    module := httptest.NewServer(todo.NewTodoAPI())
+    defer module.Close()
+
+    requested := []string{}
+    original := getModuleDetails
+    getModuleDetails = func(name string) (Module, error) {
+        requested = append(requested, name)
+        if name != "todo" {
+            return Module{}, ErrInvalidModule
+        }
+        return Module{ModuleName: "todo", ModuleRedirect: module.URL}, nil
+    }
+    defer func() { getModuleDetails = original }()
+
+    // run gateway as a server, since the reverse proxy requires
+    // a response writer that supports close notifications
+    gateway := httptest.NewServer(NewAPIGateway("secret"))
+    defer gateway.Close()
+
+    request, _ := http.NewRequest("GET", gateway.URL + "/api/TODO/items?completed=false", nil)
+    request.Header.Set("Authorization", "Bearer " + newTestToken(t, "secret"))
+    // do not follow redirects so that the redirect itself is checked
+    client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+        return http.ErrUseLastResponse
+    }}
+    response, err := client.Do(request)
+    if err != nil {
+        t.Fatalf("unable to execute request: %+v", err)
+    }
+    defer response.Body.Close()
+
+    if len(requested) != 1 || requested[0] != "todo" {
+        t.Fatalf("expected lookup of module todo, got %v", requested)
+    }
+    if response.StatusCode != http.StatusPermanentRedirect {
+        t.Fatalf("expected status %d, got %d", http.StatusPermanentRedirect, response.StatusCode)
+    }
+    if location := response.Header.Get("Location"); location != "/api/todo/items?completed=false" {
+        t.Errorf("expected redirect to /api/todo/items?completed=false, got %s", location)
+    }
+}
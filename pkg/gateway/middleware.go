@@ -3,6 +3,7 @@ package gateway
 import (
     "fmt"
     "net/http"
+    "crypto/subtle"
 
     "github.com/gin-gonic/gin"
     log "github.com/sirupsen/logrus"
@@ -44,4 +45,21 @@ func JWTMiddleware(jwtSecret string, adminOnly bool) gin.HandlerFunc {
         ctx.Set("uid", claims.Uid)
         ctx.Next()
     }
-}
\ No newline at end of file
+}
+
+// middleware used to authenticate modules registering with the
+// gateway. modules must send the shared registration secret in
+// the X-Registration-Secret header
+func ModuleRegistrationMiddleware(registrationSecret string) gin.HandlerFunc {
+    return func(ctx *gin.Context) {
+        secret := ctx.Request.Header.Get("X-Registration-Secret")
+        if len(secret) == 0 || subtle.ConstantTimeCompare([]byte(secret), []byte(registrationSecret)) != 1 {
+            log.Error("received module registration request with invalid secret")
+            ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                "http_code": http.StatusUnauthorized, "success": false,
+                "message": "Unauthorized"})
+            return
+        }
+        ctx.Next()
+    }
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
     "fmt"
+    "time"
     "errors"
 
     "github.com/neo4j/neo4j-go-driver/v4/neo4j"
@@ -35,10 +36,33 @@ func NewGraphPersistence(host string, port int,
 
 
 type Module struct {
-    ModuleName        string `json:"module_name" validate:"required"`
-    ModuleRedirect    string `json:"module_redirect" validate:"required"`
-    TrimAppName       bool   `json:"trim_app_name" validate:"required"`
-    ModuleDescription string `json:"module_description" validate:"required"`
+    ModuleName        string     `json:"module_name" validate:"required"`
+    ModuleRedirect    string     `json:"module_redirect" validate:"required"`
+    TrimAppName       bool       `json:"trim_app_name" validate:"required"`
+    ModuleDescription string     `json:"module_description" validate:"required"`
+    LeaseExpires      *time.Time `json:"lease_expires"`
+}
+
+// function used to determine if a module is available at a given
+// time. modules without a lease (i.e. modules that were inserted
+// by hand) are always considered available
+func(module Module) Available(now time.Time) bool {
+    return module.LeaseExpires == nil || now.Before(*module.LeaseExpires)
+}
+
+// function used to convert a list of values into a module
+func parseModule(values []interface{}) Module {
+    module := Module{
+        ModuleName: values[0].(string),
+        ModuleRedirect: values[1].(string),
+        ModuleDescription: values[2].(string),
+        TrimAppName: values[3].(bool),
+    }
+    if values[4] != nil {
+        expires := values[4].(time.Time)
+        module.LeaseExpires = &expires
+    }
+    return module
 }
 
 // function used to retrieve module details from graph
@@ -56,7 +80,7 @@ func(db *GraphPersistence) GetModuleDetails(name string) (Module, error) {
     handler := func(tx neo4j.Transaction) (interface{}, error) {
         query := `MATCH (n:Module{module_name: $module_name})
         RETURN n.module_name, n.module_redirect, n.module_description, 
-        n.trim_app_name LIMIT 1`
+        n.trim_app_name, n.lease_expires LIMIT 1`
 
         result, err := tx.Run(query, cfg)
         if err != nil {
@@ -77,12 +101,7 @@ func(db *GraphPersistence) GetModuleDetails(name string) (Module, error) {
         log.Error(fmt.Errorf("unable to get module details: %+v", err))
         return Module{}, err
     }
-    return Module{
-        ModuleName: result.Values[0].(string),
-        ModuleRedirect: result.Values[1].(string),
-        ModuleDescription: result.Values[2].(string),
-        TrimAppName: result.Values[3].(bool),
-    }, nil
+    return parseModule(result.Values), nil
 }
 
 // function used to add a new module to the graph. note that
@@ -119,3 +138,102 @@ func(db *GraphPersistence) AddModule(module Module) error {
     }
     return nil
 }
+
+// function used to retrieve all modules from graph
+func(db *GraphPersistence) GetModules() ([]Module, error) {
+    log.Debug("fetching all modules...")
+    modules := []Module{}
+
+    // create new persitence session for graph and defer closing
+    session := db.NewSession()
+    defer session.Close()
+    // define handle used to execute graph function
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `MATCH (n:Module)
+        RETURN n.module_name, n.module_redirect, n.module_description,
+        n.trim_app_name, n.lease_expires
+        ORDER BY n.module_name`
+        return neo4j.Collect(tx.Run(query, nil))
+    }
+    // get module details from graph using persistence session
+    results, err := neo4j.AsRecords(session.ReadTransaction(handler))
+    if err != nil {
+        log.Error(fmt.Errorf("unable to get modules: %+v", err))
+        return modules, err
+    }
+    for _, result := range results {
+        modules = append(modules, parseModule(result.Values))
+    }
+    return modules, nil
+}
+
+// function used to register a module with a lease that expires at
+// the given time. modules are created if they do not exist yet, else
+// their details are updated so that services can register themselves
+// on every startup
+func(db *GraphPersistence) RegisterModule(module Module, expires time.Time) error {
+    log.Debug(fmt.Sprintf("registering module %+v...", module))
+
+    // create new persitence session for graph and defer closing
+    session := db.NewSession()
+    defer session.Close()
+    // generate config metadata for query
+    cfg := map[string]interface{}{
+        "module_name": module.ModuleName,
+        "module_redirect": module.ModuleRedirect,
+        "module_description": module.ModuleDescription,
+        "trim_app_name": module.TrimAppName,
+        "lease_expires": expires,
+    }
+    // define handle used to execute graph function
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `MERGE (n:Module{module_name: $module_name})
+        SET n.module_redirect = $module_redirect,
+            n.module_description = $module_description,
+            n.trim_app_name = $trim_app_name,
+            n.lease_expires = $lease_expires`
+        return tx.Run(query, cfg)
+    }
+    _, err := session.WriteTransaction(handler)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to register module: %+v", err))
+        return err
+    }
+    return nil
+}
+
+// function used to renew the lease of a module. an invalid
+// module error is returned if the module is not registered
+func(db *GraphPersistence) RenewModuleLease(name string, expires time.Time) error {
+    log.Debug(fmt.Sprintf("renewing lease for module %s...", name))
+
+    // create new persitence session for graph and defer closing
+    session := db.NewSession()
+    defer session.Close()
+    // generate config metadata for query
+    cfg := map[string]interface{}{
+        "module_name": name,
+        "lease_expires": expires,
+    }
+    // define handle used to execute graph function
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `MATCH (n:Module{module_name: $module_name})
+        SET n.lease_expires = $lease_expires
+        RETURN n.module_name`
+        result, err := tx.Run(query, cfg)
+        if err != nil {
+            log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+            return nil, err
+        }
+        if _, err := neo4j.Single(result, err); err != nil {
+            return nil, ErrInvalidModule
+        }
+        return nil, nil
+    }
+    _, err := session.WriteTransaction(handler)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to renew module lease: %+v", err))
+        return err
+    }
+    return nil
+}
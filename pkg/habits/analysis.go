@@ -8,25 +8,46 @@ import (
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
 )
 
 var (
 	// define custom errors
 	ErrInvalidHabitStatus = errors.New("Habit has invalid status")
+	ErrHabitNotOverdue    = errors.New("Habit is not overdue")
+	ErrNoStreakFreezes    = errors.New("Habit has no streak freezes available")
+	ErrQuantitativeHabit  = errors.New("Habit requires progress to be logged")
+	ErrBinaryHabit        = errors.New("Habit does not have a target")
+	ErrInvalidProgress    = errors.New("Invalid progress amount")
 )
 
-// define reverse mappings for cycles to
-// convert from integer days to strings
-var reverseCycleMappings = map[int]string{
-	0: "sun",
-	1: "mon",
-	2: "tue",
-	3: "wed",
-	4: "thu",
-	5: "fri",
-	6: "sat",
+type HabitProgressResult struct {
+	Progress    float64 `json:"progress"`
+	HabitTarget float64 `json:"habit_target"`
+	HabitUnit   string  `json:"habit_unit"`
+	TargetMet   bool    `json:"target_met"`
+}
+
+type HabitStats struct {
+	HabitId       uuid.UUID `json:"habit_id"`
+	HabitTarget   float64   `json:"habit_target"`
+	HabitUnit     string    `json:"habit_unit"`
+	TotalProgress float64   `json:"total_progress"`
+	DaysLogged    int       `json:"days_logged"`
+	DaysTargetMet int       `json:"days_target_met"`
+	DailyAverage  float64   `json:"daily_average"`
+	BestDay       float64   `json:"best_day"`
 }
 
+const (
+	// define number of consecutive on-target completions
+	// required to earn a streak freeze and the maximum
+	// number of freezes that can be held at any time
+	freezeEarnInterval = 7
+	maxStreakFreezes   = 3
+)
+
 // function used to evaluate the last due date for a given habit
 // based on it last completion date
 func getHabitDueDate(habit Habit) time.Time {
@@ -43,10 +64,11 @@ func getHabitDueDate(habit Habit) time.Time {
 	// convert habit cycle to array and find last due date
 	cycle := strings.Split(habit.HabitCycle, ",")
 	for {
-		day := reverseCycleMappings[int(ts.Weekday())]
-		// if next day is present in required cycle, break out
-		// out of loop
-		if stringSliceContains(cycle, day) {
+		day := utils.ReverseCycleMappings[int(ts.Weekday())]
+		// if next day is present in required cycle and has not
+		// been excused, break out out of loop. excused days are
+		// treated as neutral and never count as due days
+		if utils.StringSliceContains(cycle, day) && !habitDayExcused(habit, ts) {
 			break
 		}
 		ts = ts.Add(time.Hour * 24)
@@ -54,35 +76,60 @@ func getHabitDueDate(habit Habit) time.Time {
 	return ts.Add(time.Hour * 24)
 }
 
+// function used to determine if a given day has been excused
+// for a habit via a skip day, vacation or streak freeze
+func habitDayExcused(habit Habit, ts time.Time) bool {
+	return utils.StringSliceContains(habit.ExcusedDays, ts.Format(excusalDateFormat))
+}
+
+// function used to evaluate the number of streak freezes held
+// by a habit after its streak has been set to the given value.
+// a new freeze is earned every time the streak reaches a multiple
+// of the earn interval, up to the maximum number of freezes
+func getEarnedFreezes(habit Habit, streak int64) int64 {
+	freezes := habit.Freezes
+	if streak > habit.Streak && streak%freezeEarnInterval == 0 && freezes < maxStreakFreezes {
+		log.Debug(fmt.Sprintf("habit %s earned streak freeze at streak %d", habit.HabitId, streak))
+		freezes++
+	}
+	return freezes
+}
+
 // function used to determine if a habit is due
-// on any given day given the
-func habitDueToday(habit Habit) bool {
+// on the day of the given reference time
+func habitDueToday(habit Habit, now time.Time) bool {
 	// get current due date for habit
 	dueDate := getHabitDueDate(habit)
 	log.Debug(fmt.Sprintf("checking if habit is due with reference due date %s", dueDate))
 	// construct theoretical due date if due today and
 	// compare to actual due date
-	year, month, day := time.Now().UTC().Date()
+	year, month, day := now.UTC().Date()
 	ts := time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
 	log.Debug(fmt.Sprintf("%s: %s", dueDate, ts))
 	return ts == dueDate
 }
 
 // function used to determine if a habit is overdue
-// based on its current due date
-func habitOverdue(habit Habit) bool {
+// at the given reference time based on its current due date
+func habitOverdue(habit Habit, now time.Time) bool {
 	// get current due date for habit
 	dueDate := getHabitDueDate(habit)
 	log.Debug(fmt.Sprintf("checking if habit is overdue with reference due date %s", dueDate))
-	return time.Now().UTC().After(dueDate)
+	return now.UTC().After(dueDate)
 }
 
 // function used to retrieve habit status. habit status
 // is returned as either due, overdue or on target
 func getHabitStatus(habit Habit) string {
-	if habitOverdue(habit) {
+	return getHabitStatusAt(habit, clock.Now())
+}
+
+// function used to retrieve habit status at a given
+// reference time. used to replay completion history
+func getHabitStatusAt(habit Habit, now time.Time) string {
+	if habitOverdue(habit, now) {
 		return "overdue"
-	} else if habitDueToday(habit) {
+	} else if habitDueToday(habit, now) {
 		return "due"
 	} else {
 		return "on-target"
@@ -98,19 +145,153 @@ func completeHabit(uid string, habitId uuid.UUID) error {
 		log.Error(fmt.Errorf("unable to retrieve habit from graph: %+v", err))
 		return err
 	}
+	// quantitative habits are only completed once
+	// enough progress has been logged for the day
+	if isQuantitativeHabit(habit) {
+		log.Warn(fmt.Sprintf("cannot complete habit %s: habit has target", habitId))
+		return ErrQuantitativeHabit
+	}
+	return recordHabitCompletion(uid, habit)
+}
+
+// function used to determine if a habit has a numeric
+// daily target instead of a single binary completion
+func isQuantitativeHabit(habit Habit) bool {
+	return habit.HabitTarget > 0
+}
 
+// function used to record a completion for a given habit. the status
+// of the habit is evaluated to determine if the streak is continued
+func recordHabitCompletion(uid string, habit Habit) error {
+	now := clock.Now()
 	log.Debug(fmt.Sprintf("habit due date evaluated as %s", getHabitDueDate(habit)))
-	switch getHabitStatus(habit) {
+	onTarget, streak, freezes, err := evaluateHabitCompletion(habit, now)
+	if err != nil {
+		return err
+	}
+	return persistence.CompleteUserHabit(uid, habit.HabitId, onTarget, streak, freezes, now)
+}
+
+// function used to evaluate the on-target flag, streak and streak
+// freezes of a habit after it is completed at the given time
+func evaluateHabitCompletion(habit Habit, now time.Time) (bool, int64, int64, error) {
+	switch getHabitStatusAt(habit, now) {
 	case "due":
 		log.Debug("habit on target. adding with streak")
-		return persistence.CompleteUserHabit(uid, habitId, true, habit.Streak+1)
+		return true, habit.Streak + 1, getEarnedFreezes(habit, habit.Streak+1), nil
 	case "overdue":
 		log.Debug("habit overdue. adding without streak")
-		return persistence.CompleteUserHabit(uid, habitId, false, 0)
+		return false, 0, habit.Freezes, nil
 	case "on-target":
 		log.Debug("habit already on target. adding without streak")
-		return persistence.CompleteUserHabit(uid, habitId, true, habit.Streak)
+		return true, habit.Streak, habit.Freezes, nil
 	default:
-		return ErrInvalidHabitStatus
+		return false, 0, 0, ErrInvalidHabitStatus
+	}
+}
+
+// function used to log progress towards the daily target of a
+// quantitative habit. the habit is completed as soon as the total
+// progress for the current day reaches the target
+func logHabitProgress(uid string, habitId uuid.UUID, amount float64) (HabitProgressResult, error) {
+	// get current habit from graph
+	habit, err := persistence.GetHabitByHabitId(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit from graph: %+v", err))
+		return HabitProgressResult{}, err
+	}
+
+	if !isQuantitativeHabit(habit) {
+		log.Warn(fmt.Sprintf("cannot log progress for habit %s: habit has no target", habitId))
+		return HabitProgressResult{}, ErrBinaryHabit
+	}
+	if amount <= 0 {
+		return HabitProgressResult{}, ErrInvalidProgress
+	}
+
+	today := clock.Now().Format(excusalDateFormat)
+	previous, total, err := persistence.AddHabitProgress(uid, habitId, amount, today)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to log habit progress: %+v", err))
+		return HabitProgressResult{}, err
+	}
+	// complete habit only when the target is crossed so that
+	// further progress on the same day does not add completions.
+	// the previous total is taken from the same statement as the
+	// increment so that concurrent updates cannot both complete
+	if previous < habit.HabitTarget && total >= habit.HabitTarget {
+		log.Debug(fmt.Sprintf("daily target met for habit %s. completing habit", habitId))
+		if err := recordHabitCompletion(uid, habit); err != nil {
+			return HabitProgressResult{}, err
+		}
+	}
+	return HabitProgressResult{
+		Progress:    total,
+		HabitTarget: habit.HabitTarget,
+		HabitUnit:   habit.HabitUnit,
+		TargetMet:   total >= habit.HabitTarget,
+	}, nil
+}
+
+// function used to evaluate progress statistics for a habit.
+// totals and averages are evaluated over days with progress
+func getHabitStats(uid string, habitId uuid.UUID) (HabitStats, error) {
+	habit, err := persistence.GetHabitByHabitId(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit from graph: %+v", err))
+		return HabitStats{}, err
+	}
+	totals, err := persistence.GetHabitDailyTotals(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit progress totals: %+v", err))
+		return HabitStats{}, err
+	}
+
+	stats := HabitStats{
+		HabitId:     habitId,
+		HabitTarget: habit.HabitTarget,
+		HabitUnit:   habit.HabitUnit,
+		DaysLogged:  len(totals),
+	}
+	for _, day := range totals {
+		stats.TotalProgress += day.Total
+		if day.Total >= habit.HabitTarget {
+			stats.DaysTargetMet++
+		}
+		if day.Total > stats.BestDay {
+			stats.BestDay = day.Total
+		}
+	}
+	if stats.DaysLogged > 0 {
+		stats.DailyAverage = stats.TotalProgress / float64(stats.DaysLogged)
+	}
+	return stats, nil
+}
+
+// function used to spend a streak freeze on an overdue habit. the
+// freeze excuses the missed due day, so that the streak is kept when
+// the habit is next completed. the excused date is returned
+func freezeHabit(uid string, habitId uuid.UUID) (string, error) {
+	// get current habit from graph
+	habit, err := persistence.GetHabitByHabitId(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit from graph: %+v", err))
+		return "", err
+	}
+
+	if getHabitStatus(habit) != "overdue" {
+		log.Warn(fmt.Sprintf("cannot freeze habit %s: habit is not overdue", habitId))
+		return "", ErrHabitNotOverdue
+	}
+	if habit.Freezes < 1 {
+		log.Warn(fmt.Sprintf("cannot freeze habit %s: no freezes available", habitId))
+		return "", ErrNoStreakFreezes
+	}
+	// missed day is the day preceding the current due date
+	missed := getHabitDueDate(habit).Add(-time.Hour * 24).Format(excusalDateFormat)
+	log.Debug(fmt.Sprintf("spending streak freeze on %s for habit %s", missed, habitId))
+	if err := persistence.SpendHabitFreeze(uid, habitId, missed); err != nil {
+		return "", err
 	}
+	return missed, nil
 }
@@ -0,0 +1,325 @@
+package habits
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+// define clock that is moved forward manually by tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// 2024-01-01 is a monday, so day offsets map directly onto weekdays
+var simulationStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	dailyCycle    = "mon,tue,wed,thu,fri,sat,sun"
+	weekdayCycle  = "mon,tue,wed,thu,fri"
+	weekendCycle  = "sat,sun"
+	alternateDays = "mon,wed,fri"
+	weeklyCycle   = "mon"
+)
+
+// function used to complete a habit on all days of its cycle
+func onCycleDays(cycle string) func(int, time.Time) bool {
+	days := strings.Split(cycle, ",")
+	return func(_ int, ts time.Time) bool {
+		return utils.StringSliceContains(days, utils.ReverseCycleMappings[int(ts.Weekday())])
+	}
+}
+
+// function used to complete a habit on all days except the given offsets
+func exceptDays(offsets ...int) func(int, time.Time) bool {
+	return func(day int, _ time.Time) bool {
+		for _, offset := range offsets {
+			if day == offset {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// function used to complete a habit only on the given day offsets
+func onDays(offsets ...int) func(int, time.Time) bool {
+	return func(day int, _ time.Time) bool {
+		for _, offset := range offsets {
+			if day == offset {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestHabitCompletionSimulation(t *testing.T) {
+	cases := []struct {
+		name     string
+		cycle    string
+		excused  []string
+		days     int
+		complete func(int, time.Time) bool
+		// offset from the start of the day following the
+		// simulation at which the final status is evaluated
+		statusAt      time.Duration
+		wantStatus    string
+		wantStreak    int64
+		wantLongest   int64
+		wantFreezes   int64
+		wantReconcile int64
+	}{
+		{
+			name:          "daily habit completed every day for four weeks",
+			cycle:         dailyCycle,
+			days:          28,
+			complete:      onCycleDays(dailyCycle),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    28,
+			wantLongest:   28,
+			wantFreezes:   3,
+			wantReconcile: 28,
+		},
+		{
+			name:          "daily habit with a missed day",
+			cycle:         dailyCycle,
+			days:          14,
+			complete:      exceptDays(5),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    7,
+			wantLongest:   7,
+			wantFreezes:   1,
+			wantReconcile: 7,
+		},
+		{
+			name:          "daily habit with an excused day",
+			cycle:         dailyCycle,
+			excused:       []string{"2024-01-06"},
+			days:          14,
+			complete:      exceptDays(5),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    13,
+			wantLongest:   13,
+			wantFreezes:   1,
+			wantReconcile: 13,
+		},
+		{
+			name:          "daily habit abandoned after one week",
+			cycle:         dailyCycle,
+			days:          14,
+			complete:      onDays(0, 1, 2, 3, 4, 5, 6),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "overdue",
+			wantStreak:    7,
+			wantLongest:   7,
+			wantFreezes:   1,
+			wantReconcile: 0,
+		},
+		{
+			name:          "weekday habit completed on weekdays for two weeks",
+			cycle:         weekdayCycle,
+			days:          12,
+			complete:      onCycleDays(weekdayCycle),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "on-target",
+			wantStreak:    10,
+			wantLongest:   10,
+			wantFreezes:   1,
+			wantReconcile: 10,
+		},
+		{
+			name:          "weekday habit missed on a friday",
+			cycle:         weekdayCycle,
+			days:          14,
+			complete:      onDays(0, 1, 2, 3, 7, 8, 9, 10, 11),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    4,
+			wantLongest:   4,
+			wantFreezes:   0,
+			wantReconcile: 4,
+		},
+		{
+			name:          "alternate day habit completed on cycle days",
+			cycle:         alternateDays,
+			days:          21,
+			complete:      onCycleDays(alternateDays),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    9,
+			wantLongest:   9,
+			wantFreezes:   1,
+			wantReconcile: 9,
+		},
+		{
+			name:          "alternate day habit also completed off cycle",
+			cycle:         alternateDays,
+			days:          14,
+			complete:      exceptDays(),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "due",
+			wantStreak:    6,
+			wantLongest:   6,
+			wantFreezes:   0,
+			wantReconcile: 6,
+		},
+		{
+			name:          "weekend habit completed on weekends",
+			cycle:         weekendCycle,
+			days:          21,
+			complete:      onCycleDays(weekendCycle),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "on-target",
+			wantStreak:    6,
+			wantLongest:   6,
+			wantFreezes:   0,
+			wantReconcile: 6,
+		},
+		{
+			name:          "weekend habit never completed",
+			cycle:         weekendCycle,
+			days:          7,
+			complete:      onDays(),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "overdue",
+			wantStreak:    0,
+			wantLongest:   0,
+			wantFreezes:   0,
+			wantReconcile: 0,
+		},
+		{
+			name:          "weekly habit with a skipped week",
+			cycle:         weeklyCycle,
+			days:          15,
+			complete:      onDays(0, 14),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "on-target",
+			wantStreak:    0,
+			wantLongest:   1,
+			wantFreezes:   0,
+			wantReconcile: 0,
+		},
+		{
+			name:          "weekly habit completed every week",
+			cycle:         weeklyCycle,
+			days:          29,
+			complete:      onCycleDays(weeklyCycle),
+			statusAt:      time.Hour * 12,
+			wantStatus:    "on-target",
+			wantStreak:    5,
+			wantLongest:   5,
+			wantFreezes:   0,
+			wantReconcile: 5,
+		},
+	}
+
+	defer SetClock(nil)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &fakeClock{}
+			SetClock(c)
+
+			habit := Habit{
+				HabitName:   tc.name,
+				HabitCycle:  tc.cycle,
+				Created:     simulationStart,
+				ExcusedDays: tc.excused,
+			}
+			completions := []time.Time{}
+			// simulate habit completions at 09:00 on each day
+			for day := 0; day < tc.days; day++ {
+				c.now = simulationStart.Add(time.Hour*24*time.Duration(day) + time.Hour*9)
+				if !tc.complete(day, c.now) {
+					continue
+				}
+				_, streak, freezes, err := evaluateHabitCompletion(habit, c.Now())
+				if err != nil {
+					t.Fatalf("unable to complete habit on day %d: %+v", day, err)
+				}
+				completed := c.Now()
+				habit.LastCompleted = &completed
+				habit.Streak = streak
+				habit.Freezes = freezes
+				if streak > habit.LongestStreak {
+					habit.LongestStreak = streak
+				}
+				completions = append(completions, completed)
+			}
+
+			c.now = simulationStart.Add(time.Hour*24*time.Duration(tc.days) + tc.statusAt)
+			if status := getHabitStatus(habit); status != tc.wantStatus {
+				t.Errorf("expected status %s but got %s", tc.wantStatus, status)
+			}
+			if habit.Streak != tc.wantStreak {
+				t.Errorf("expected streak %d but got %d", tc.wantStreak, habit.Streak)
+			}
+			if habit.LongestStreak != tc.wantLongest {
+				t.Errorf("expected longest streak %d but got %d", tc.wantLongest, habit.LongestStreak)
+			}
+			if habit.Freezes != tc.wantFreezes {
+				t.Errorf("expected %d freezes but got %d", tc.wantFreezes, habit.Freezes)
+			}
+
+			// replaying completion history must agree with the
+			// values evaluated as the habit was completed
+			reconciliation := reconcileHabit(HabitHistory{Habit: habit, Completions: completions}, c.Now())
+			if reconciliation.Status != tc.wantStatus {
+				t.Errorf("expected reconciled status %s but got %s", tc.wantStatus, reconciliation.Status)
+			}
+			if reconciliation.Streak != tc.wantReconcile {
+				t.Errorf("expected reconciled streak %d but got %d", tc.wantReconcile, reconciliation.Streak)
+			}
+			if reconciliation.LongestStreak != tc.wantLongest {
+				t.Errorf("expected reconciled longest streak %d but got %d",
+					tc.wantLongest, reconciliation.LongestStreak)
+			}
+		})
+	}
+}
+
+func TestHabitStatusAcrossDays(t *testing.T) {
+	cases := []struct {
+		name       string
+		cycle      string
+		completed  time.Duration
+		now        time.Duration
+		wantStatus string
+	}{
+		{"new daily habit on first day", dailyCycle, -1, time.Hour * 9, "due"},
+		{"new daily habit on second day", dailyCycle, -1, time.Hour * 33, "overdue"},
+		{"daily habit completed today", dailyCycle, time.Hour * 9, time.Hour * 23, "on-target"},
+		{"daily habit completed yesterday", dailyCycle, time.Hour * 9, time.Hour * 33, "due"},
+		{"daily habit completed two days ago", dailyCycle, time.Hour * 9, time.Hour * 57, "overdue"},
+		{"weekday habit completed friday on saturday", weekdayCycle, time.Hour * (24*4 + 9), time.Hour * (24*5 + 9), "on-target"},
+		{"weekday habit completed friday on monday", weekdayCycle, time.Hour * (24*4 + 9), time.Hour * (24*7 + 9), "due"},
+		{"weekday habit completed friday on tuesday", weekdayCycle, time.Hour * (24*4 + 9), time.Hour * (24*8 + 9), "overdue"},
+		{"new weekend habit on monday", weekendCycle, -1, time.Hour * 9, "on-target"},
+		{"new weekend habit on saturday", weekendCycle, -1, time.Hour * (24*5 + 9), "due"},
+		{"new weekend habit on monday after", weekendCycle, -1, time.Hour * (24*7 + 9), "overdue"},
+	}
+
+	defer SetClock(nil)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetClock(&fakeClock{now: simulationStart.Add(tc.now)})
+			habit := Habit{HabitCycle: tc.cycle, Created: simulationStart}
+			if tc.completed >= 0 {
+				completed := simulationStart.Add(tc.completed)
+				habit.LastCompleted = &completed
+			}
+			if status := getHabitStatus(habit); status != tc.wantStatus {
+				t.Errorf("expected status %s but got %s", tc.wantStatus, status)
+			}
+		})
+	}
+}
@@ -9,12 +9,22 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/PSauerborn/lifelink/pkg/utils"
+	api "github.com/PSauerborn/lifelink/pkg/utils/accessors"
 )
 
-var persistence *GraphPersistence
+var (
+	// define global persistence layer
+	persistence *GraphPersistence
+
+	// define global accessor for TODO API
+	todoAPIAccessor *api.TodoAPIAccessor
+)
+
+// function used to generate new API. the TODO API is
+// used to access TODO items linked to goals and habits
+func NewHabitsAPI(todoCfg utils.APIDependencyConfig) *gin.Engine {
+	todoAPIAccessor = api.NewTodoApiAccessorFromConfig(todoCfg)
 
-// function used to generate new API
-func NewHabitsAPI() *gin.Engine {
 	router := gin.Default()
 	// add middleware to inject user ID into request context
 	router.Use(utils.UserInjectionMiddleware())
@@ -27,6 +37,66 @@ func NewHabitsAPI() *gin.Engine {
 	router.PUT("/habits/update/:habitId", updateHabitHandler)
 	router.PATCH("/habits/complete/:habitId", completeHabitHandler)
 	router.DELETE("/habits/delete/:habitId", deleteHabitHandler)
+
+	router.PATCH("/habits/archive/:habitId", archiveHabitHandler)
+	router.PATCH("/habits/unarchive/:habitId", unarchiveHabitHandler)
+	router.GET("/habits/trash", getTrashedHabitsHandler)
+	router.PATCH("/habits/restore/:habitId", restoreHabitHandler)
+	router.DELETE("/habits/trash/:habitId", purgeHabitHandler)
+
+	router.GET("/habits/excusals/:habitId", getHabitExcusalsHandler)
+	router.POST("/habits/skip/:habitId", skipHabitDaysHandler)
+	router.POST("/habits/freeze/:habitId", freezeHabitHandler)
+	router.POST("/habits/vacation", vacationHandler)
+	router.DELETE("/habits/excusals/:habitId/:date", deleteHabitExcusalHandler)
+
+	router.GET("/habits/progress/:habitId", getHabitProgressHandler)
+	router.POST("/habits/progress/:habitId", logHabitProgressHandler)
+	router.GET("/habits/stats/:habitId", getHabitStatsHandler)
+	router.GET("/habits/insights", getInsightsHandler)
+
+	router.GET("/habits/reminders", getReminderSettingsHandler)
+	router.PUT("/habits/reminders", setReminderSettingsHandler)
+
+	router.GET("/habits/shared", getSharedHabitsHandler)
+	router.GET("/habits/partners/:habitId", getHabitPartnersHandler)
+	router.POST("/habits/partners/:habitId", inviteHabitPartnerHandler)
+	router.DELETE("/habits/partners/:habitId/:partner", removeHabitPartnerHandler)
+	router.GET("/habits/invitations", getPartnerInvitationsHandler)
+	router.PATCH("/habits/invitations/:habitId/accept", acceptPartnerInvitationHandler)
+	router.PATCH("/habits/invitations/:habitId/decline", declinePartnerInvitationHandler)
+
+	router.GET("/habits/challenges", getChallengesHandler)
+	router.POST("/habits/challenges", createChallengeHandler)
+	router.GET("/habits/challenges/:challengeId", getChallengeHandler)
+	router.PATCH("/habits/challenges/:challengeId/join", joinChallengeHandler)
+	router.PATCH("/habits/challenges/:challengeId/leave", leaveChallengeHandler)
+	router.GET("/habits/challenges/:challengeId/leaderboard", getChallengeLeaderboardHandler)
+
+	router.GET("/habits/templates", getHabitTemplatesHandler)
+	router.POST("/habits/templates", publishHabitTemplateHandler)
+	router.DELETE("/habits/templates/:templateId", deleteHabitTemplateHandler)
+	router.POST("/habits/templates/instantiate", instantiateHabitTemplatesHandler)
+	router.GET("/habits/bundles", getHabitTemplateBundlesHandler)
+	router.POST("/habits/bundles/:bundleId/instantiate", instantiateHabitTemplateBundleHandler)
+
+	router.GET("/habits/goals", getGoalsHandler)
+	router.POST("/habits/goals", createGoalHandler)
+	router.GET("/habits/goals/:goalId", getGoalHandler)
+	router.PUT("/habits/goals/:goalId", updateGoalHandler)
+	router.DELETE("/habits/goals/:goalId", deleteGoalHandler)
+	router.PUT("/habits/goals/:goalId/habits/:habitId", linkGoalHabitHandler)
+	router.DELETE("/habits/goals/:goalId/habits/:habitId", unlinkGoalHabitHandler)
+	router.PUT("/habits/goals/:goalId/items/:itemId", linkGoalTodoItemHandler)
+	router.DELETE("/habits/goals/:goalId/items/:itemId", unlinkGoalTodoItemHandler)
+	router.POST("/habits/promote/:itemId", promoteTodoItemHandler)
+
+	// define admin-only routes used to curate template catalogue
+	admin := router.Group("/habits/admin", AdminProtected())
+	admin.POST("/templates", createSystemHabitTemplateHandler)
+	admin.DELETE("/templates/:templateId", adminDeleteHabitTemplateHandler)
+	admin.POST("/bundles", createHabitTemplateBundleHandler)
+	admin.DELETE("/bundles/:bundleId", deleteHabitTemplateBundleHandler)
 	return router
 }
 
@@ -57,8 +127,10 @@ func getHabitsHandler(ctx *gin.Context) {
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
 
-	// get habits from graph for given user
-	habits, err := persistence.GetUserHabits(uid)
+	// get habits from graph for given user. archived habits
+	// are only included if requested via query parameter
+	includeArchived := ctx.Query("archived") == "true"
+	habits, err := persistence.GetUserHabits(uid, includeArchived)
 	if err != nil {
 		log.Error(fmt.Errorf("unable to retreive user habits: %+v", err))
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
@@ -90,6 +162,14 @@ func createHabitHandler(ctx *gin.Context) {
 			"message": "Invalid habit cycle"})
 		return
 	}
+	// check that given habit target is valid
+	if request.HabitTarget < 0 {
+		log.Error(fmt.Sprintf("received invalid target %f", request.HabitTarget))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit target"})
+		return
+	}
 
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
@@ -107,7 +187,7 @@ func createHabitHandler(ctx *gin.Context) {
 // function used to generate a new habit for given user
 func updateHabitHandler(ctx *gin.Context) {
 	log.Info("received request to update habit")
-	var request Habit
+	var request HabitUpdate
 	// parse habit ID from request path
 	habitId, err := uuid.Parse(ctx.Param("habitId"))
 	if err != nil {
@@ -134,6 +214,14 @@ func updateHabitHandler(ctx *gin.Context) {
 			"message": "Invalid habit cycle"})
 		return
 	}
+	// check that given habit target is valid
+	if request.HabitTarget != nil && *request.HabitTarget < 0 {
+		log.Error(fmt.Sprintf("received invalid target %f", *request.HabitTarget))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit target"})
+		return
+	}
 
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
@@ -164,16 +252,23 @@ func completeHabitHandler(ctx *gin.Context) {
 	uid := ctx.MustGet("uid").(string)
 	if err := completeHabit(uid, habitId); err != nil {
 		log.Error(fmt.Errorf("unable to complete user habit: %+v", err))
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"http_code": http.StatusInternalServerError, "success": false,
-			"message": "Internal server error"})
+		switch err {
+		case ErrQuantitativeHabit:
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"http_code": http.StatusBadRequest, "success": false,
+				"message": "Habit has target: progress must be logged"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
 		"success": true, "message": "Successfully completed habit"})
 }
 
-// function used to delete a habit with given habit ID
+// function used to move a habit with given habit ID to the trash
 func deleteHabitHandler(ctx *gin.Context) {
 	log.Info("received request to delete habit")
 	habitId, err := uuid.Parse(ctx.Param("habitId"))
@@ -189,11 +284,1462 @@ func deleteHabitHandler(ctx *gin.Context) {
 	uid := ctx.MustGet("uid").(string)
 	if err := persistence.DeleteUserHabit(uid, habitId); err != nil {
 		log.Error(fmt.Errorf("unable to delete user habit: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted habit"})
+}
+
+// function used to retrieve all excused days for a habit
+func getHabitExcusalsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit excusals")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	excusals, err := persistence.GetHabitExcusals(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit excusals: %+v", err))
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"http_code": http.StatusInternalServerError, "success": false,
 			"message": "Internal server error"})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
-		"success": true, "message": "Successfully deleted habit"})
+		"success": true, "excusals": excusals})
+}
+
+// function used to mark a set of days as planned skip
+// days for a habit with given habit ID
+func skipHabitDaysHandler(ctx *gin.Context) {
+	log.Info("received request to skip habit days")
+	var request struct {
+		Dates []string `json:"dates" binding:"required"`
+	}
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+	// parse request body
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context and evaluate current
+	// day in the timezone of the user
+	uid := ctx.MustGet("uid").(string)
+	loc, err := userLocation(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve user timezone: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	dates, err := parseExcusalDates(request.Dates, clock.Now().In(loc).Format(excusalDateFormat))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid skip days %+v", request.Dates))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": invalidExcusalMessage(err)})
+		return
+	}
+
+	if err := persistence.AddHabitExcusals(uid, habitId, dates, "skip"); err != nil {
+		log.Error(fmt.Errorf("unable to skip habit days: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "message": "Successfully skipped habit days"})
+}
+
+// function used to spend a streak freeze on an overdue habit
+func freezeHabitHandler(ctx *gin.Context) {
+	log.Info("received request to freeze habit")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	date, err := freezeHabit(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to freeze user habit: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		case ErrHabitNotOverdue, ErrNoStreakFreezes:
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"http_code": http.StatusConflict, "success": false,
+				"message": err.Error()})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "excusal_date": date})
+}
+
+// function used to put all habits for a user into vacation
+// mode for a given (inclusive) date range
+func vacationHandler(ctx *gin.Context) {
+	log.Info("received request to set vacation days")
+	var request struct {
+		Start string `json:"start" binding:"required"`
+		End   string `json:"end" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context and evaluate current
+	// day in the timezone of the user
+	uid := ctx.MustGet("uid").(string)
+	loc, err := userLocation(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve user timezone: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	dates, err := expandDateRange(request.Start, request.End, clock.Now().In(loc).Format(excusalDateFormat))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid vacation range %s - %s", request.Start, request.End))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": invalidExcusalMessage(err)})
+		return
+	}
+
+	if err := persistence.AddVacationExcusals(uid, dates); err != nil {
+		log.Error(fmt.Errorf("unable to set vacation days: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "message": "Successfully set vacation days"})
+}
+
+// function used to generate the error message returned
+// for skip and vacation days that fail validation
+func invalidExcusalMessage(err error) string {
+	if err == ErrPastExcusalDate {
+		return "Dates must not be in the past"
+	}
+	return "Invalid dates"
+}
+
+// function used to remove a planned skip or vacation day
+func deleteHabitExcusalHandler(ctx *gin.Context) {
+	log.Info("received request to delete habit excusal")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteHabitExcusal(uid, habitId, ctx.Param("date")); err != nil {
+		log.Error(fmt.Errorf("unable to delete habit excusal: %+v", err))
+		switch err {
+		case ErrExcusalNotFound:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find excusal"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted habit excusal"})
+}
+
+// function used to retrieve all progress entries for a habit
+func getHabitProgressHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit progress")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	progress, err := persistence.GetHabitProgress(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit progress: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "progress": progress})
+}
+
+// function used to log progress towards the daily
+// target of a habit with given habit ID
+func logHabitProgressHandler(ctx *gin.Context) {
+	log.Info("received request to log habit progress")
+	var request struct {
+		Amount float64 `json:"amount" binding:"required"`
+	}
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+	// parse request body
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	result, err := logHabitProgress(uid, habitId, request.Amount)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to log habit progress: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		case ErrBinaryHabit, ErrInvalidProgress:
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"http_code": http.StatusBadRequest, "success": false,
+				"message": err.Error()})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "result": result})
+}
+
+// function used to retrieve progress statistics for a habit
+func getHabitStatsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit stats")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	stats, err := getHabitStats(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit stats: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "stats": stats})
+}
+
+// function used to archive a habit with given habit ID
+func archiveHabitHandler(ctx *gin.Context) {
+	log.Info("received request to archive habit")
+	setHabitArchived(ctx, true)
+}
+
+// function used to restore an archived habit with given habit ID
+func unarchiveHabitHandler(ctx *gin.Context) {
+	log.Info("received request to unarchive habit")
+	setHabitArchived(ctx, false)
+}
+
+// function used to set the archived flag on a habit
+// with the habit ID given in the request path
+func setHabitArchived(ctx *gin.Context, archived bool) {
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetUserHabitArchived(uid, habitId, archived); err != nil {
+		log.Error(fmt.Errorf("unable to archive user habit: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated habit"})
+}
+
+// function used to retrieve all habits in the trash
+func getTrashedHabitsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve trashed habits")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	habits, err := persistence.GetTrashedHabits(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive trashed habits: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "habits": habits})
+}
+
+// function used to restore a habit with given habit ID from the trash
+func restoreHabitHandler(ctx *gin.Context) {
+	log.Info("received request to restore habit")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.RestoreUserHabit(uid, habitId); err != nil {
+		log.Error(fmt.Errorf("unable to restore user habit: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit in trash"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully restored habit"})
+}
+
+// function used to permanently delete a habit in the trash
+func purgeHabitHandler(ctx *gin.Context) {
+	log.Info("received request to purge habit")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.PurgeUserHabit(uid, habitId); err != nil {
+		log.Error(fmt.Errorf("unable to purge user habit: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit in trash"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted habit"})
+}
+
+// function used to retrieve reminder settings for a user
+func getReminderSettingsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve reminder settings")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	settings, err := persistence.GetReminderSettings(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve reminder settings: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "settings": settings})
+}
+
+// function used to set reminder times for a user. timezones
+// are set with the users API
+func setReminderSettingsHandler(ctx *gin.Context) {
+	log.Info("received request to set reminder settings")
+	var request ReminderSettings
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	if err := validateReminderSettings(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid reminder settings"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetReminderSettings(uid, request); err != nil {
+		log.Error(fmt.Errorf("unable to set reminder settings: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated reminder settings"})
+}
+
+// function used to retrieve all habits shared with a user
+func getSharedHabitsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve shared habits")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	habits, err := persistence.GetSharedHabits(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive shared habits: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "habits": habits})
+}
+
+// function used to retrieve all accountability partners on a habit
+func getHabitPartnersHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit partners")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	partners, err := persistence.GetHabitPartners(uid, habitId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit partners: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "partners": partners})
+}
+
+// function used to invite a user as accountability partner on a habit
+func inviteHabitPartnerHandler(ctx *gin.Context) {
+	log.Info("received request to invite habit partner")
+	var request struct {
+		Uid string `json:"uid" binding:"required"`
+	}
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+	// parse request body
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.InviteHabitPartner(uid, habitId, request.Uid); err != nil {
+		log.Error(fmt.Errorf("unable to invite habit partner: %+v", err))
+		switch err {
+		case ErrHabitDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find habit"})
+		case ErrPartnerDoesNotExist, ErrInvalidPartner:
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"http_code": http.StatusBadRequest, "success": false,
+				"message": "Invalid partner"})
+		case ErrPartnerAlreadyInvited:
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"http_code": http.StatusConflict, "success": false,
+				"message": "Partner already invited"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "message": "Successfully invited partner"})
+}
+
+// function used to remove an accountability partner from a habit
+func removeHabitPartnerHandler(ctx *gin.Context) {
+	log.Info("received request to remove habit partner")
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.RemoveHabitPartner(uid, habitId, ctx.Param("partner")); err != nil {
+		log.Error(fmt.Errorf("unable to remove habit partner: %+v", err))
+		switch err {
+		case ErrPartnerDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find partner"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully removed partner"})
+}
+
+// function used to retrieve pending partner invitations for a user
+func getPartnerInvitationsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve partner invitations")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	invitations, err := persistence.GetPartnerInvitations(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive partner invitations: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "invitations": invitations})
+}
+
+// function used to accept a partner invitation for a habit
+func acceptPartnerInvitationHandler(ctx *gin.Context) {
+	log.Info("received request to accept partner invitation")
+	respondToPartnerInvitation(ctx, persistence.AcceptPartnerInvitation)
+}
+
+// function used to decline a partner invitation for a habit
+func declinePartnerInvitationHandler(ctx *gin.Context) {
+	log.Info("received request to decline partner invitation")
+	respondToPartnerInvitation(ctx, persistence.DeclinePartnerInvitation)
+}
+
+// function used to accept or decline a partner invitation for
+// the habit ID given in the request path
+func respondToPartnerInvitation(ctx *gin.Context, respond func(string, uuid.UUID) error) {
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := respond(uid, habitId); err != nil {
+		log.Error(fmt.Errorf("unable to respond to partner invitation: %+v", err))
+		switch err {
+		case ErrInvitationNotFound:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find invitation"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated invitation"})
+}
+
+// function used to retrieve all challenges a user participates in
+func getChallengesHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve challenges")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	challenges, err := persistence.GetUserChallenges(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive challenges: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "challenges": challenges})
+}
+
+// function used to create a new challenge
+func createChallengeHandler(ctx *gin.Context) {
+	log.Info("received request to create new challenge")
+	var request Challenge
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	challenge, err := prepareChallenge(request)
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid challenge %+v", request))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid challenge"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	challengeId, err := persistence.CreateChallenge(uid, challenge)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create challenge for user %s: %+v", uid, err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "challenge_id": challengeId})
+}
+
+// function used to retrieve a challenge with given challenge ID
+func getChallengeHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve challenge")
+	challengeId, err := uuid.Parse(ctx.Param("challengeId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid challenge ID %s", ctx.Param("challengeId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid challenge ID"})
+		return
+	}
+
+	challenge, err := persistence.GetChallenge(challengeId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve challenge: %+v", err))
+		switch err {
+		case ErrChallengeDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find challenge"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "challenge": challenge})
+}
+
+// function used to join a challenge with given challenge ID
+func joinChallengeHandler(ctx *gin.Context) {
+	log.Info("received request to join challenge")
+	challengeId, err := uuid.Parse(ctx.Param("challengeId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid challenge ID %s", ctx.Param("challengeId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid challenge ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := joinChallenge(uid, challengeId); err != nil {
+		log.Error(fmt.Errorf("unable to join challenge: %+v", err))
+		switch err {
+		case ErrChallengeDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find challenge"})
+		case ErrChallengeEnded, ErrAlreadyParticipant:
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"http_code": http.StatusConflict, "success": false,
+				"message": err.Error()})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully joined challenge"})
+}
+
+// function used to leave a challenge with given challenge ID
+func leaveChallengeHandler(ctx *gin.Context) {
+	log.Info("received request to leave challenge")
+	challengeId, err := uuid.Parse(ctx.Param("challengeId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid challenge ID %s", ctx.Param("challengeId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid challenge ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.LeaveChallenge(uid, challengeId); err != nil {
+		log.Error(fmt.Errorf("unable to leave challenge: %+v", err))
+		switch err {
+		case ErrNotParticipant:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "User does not participate in challenge"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully left challenge"})
+}
+
+// function used to retrieve the leaderboard for a challenge
+func getChallengeLeaderboardHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve challenge leaderboard")
+	challengeId, err := uuid.Parse(ctx.Param("challengeId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid challenge ID %s", ctx.Param("challengeId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid challenge ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	leaderboard, err := getChallengeLeaderboard(uid, challengeId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve challenge leaderboard: %+v", err))
+		switch err {
+		case ErrChallengeDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find challenge"})
+		case ErrNotParticipant:
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"http_code": http.StatusForbidden, "success": false,
+				"message": "Forbidden"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "leaderboard": leaderboard})
+}
+
+// function used to retrieve the habit template catalogue
+func getHabitTemplatesHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit templates")
+	templates, err := persistence.GetHabitTemplates()
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive habit templates: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "templates": templates})
+}
+
+// function used to publish a new user template to the catalogue
+func publishHabitTemplateHandler(ctx *gin.Context) {
+	log.Info("received request to publish habit template")
+	createHabitTemplate(ctx, false)
+}
+
+// function used to add a new system template to the catalogue
+func createSystemHabitTemplateHandler(ctx *gin.Context) {
+	log.Info("received request to create system habit template")
+	createHabitTemplate(ctx, true)
+}
+
+// function used to parse a habit template from the request
+// body and add it to the template catalogue
+func createHabitTemplate(ctx *gin.Context, system bool) {
+	var request HabitTemplate
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// check that given habit cycle and target are valid
+	if !isValidCycle(request.HabitCycle) || request.HabitTarget < 0 {
+		log.Error(fmt.Sprintf("received invalid template %+v", request))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit template"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	templateId, err := persistence.CreateHabitTemplate(uid, request, system)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create habit template: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "template_id": templateId})
+}
+
+// function used to delete a template published by a user
+func deleteHabitTemplateHandler(ctx *gin.Context) {
+	log.Info("received request to delete habit template")
+	deleteHabitTemplate(ctx, false)
+}
+
+// function used to delete any template from the catalogue
+func adminDeleteHabitTemplateHandler(ctx *gin.Context) {
+	log.Info("received admin request to delete habit template")
+	deleteHabitTemplate(ctx, true)
+}
+
+// function used to delete the template with the
+// template ID given in the request path
+func deleteHabitTemplate(ctx *gin.Context, admin bool) {
+	templateId, err := uuid.Parse(ctx.Param("templateId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid template ID %s", ctx.Param("templateId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid template ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteHabitTemplate(uid, templateId, admin); err != nil {
+		log.Error(fmt.Errorf("unable to delete habit template: %+v", err))
+		switch err {
+		case ErrTemplateDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find template"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted template"})
+}
+
+// function used to create habits from one or more templates
+func instantiateHabitTemplatesHandler(ctx *gin.Context) {
+	log.Info("received request to instantiate habit templates")
+	var request struct {
+		TemplateIds []uuid.UUID `json:"template_ids" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil || len(request.TemplateIds) == 0 {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	created, err := persistence.InstantiateHabitTemplates(uid, request.TemplateIds)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to instantiate habit templates: %+v", err))
+		switch err {
+		case ErrTemplateDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find template"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "created": created})
+}
+
+// function used to retrieve all template bundles
+func getHabitTemplateBundlesHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit template bundles")
+	bundles, err := persistence.GetHabitTemplateBundles()
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retreive habit template bundles: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "bundles": bundles})
+}
+
+// function used to create habits from all templates in a bundle
+func instantiateHabitTemplateBundleHandler(ctx *gin.Context) {
+	log.Info("received request to instantiate habit template bundle")
+	bundleId, err := uuid.Parse(ctx.Param("bundleId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid bundle ID %s", ctx.Param("bundleId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid bundle ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	created, err := persistence.InstantiateHabitTemplateBundle(uid, bundleId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to instantiate habit template bundle: %+v", err))
+		switch err {
+		case ErrBundleDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find bundle"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "created": created})
+}
+
+// function used to create a new template bundle
+func createHabitTemplateBundleHandler(ctx *gin.Context) {
+	log.Info("received request to create habit template bundle")
+	var request HabitTemplateBundle
+	if err := ctx.ShouldBind(&request); err != nil || len(request.TemplateIds) == 0 {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	bundleId, err := persistence.CreateHabitTemplateBundle(request)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create habit template bundle: %+v", err))
+		switch err {
+		case ErrTemplateDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"http_code": http.StatusBadRequest, "success": false,
+				"message": "Cannot find template"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "bundle_id": bundleId})
+}
+
+// function used to delete a template bundle
+func deleteHabitTemplateBundleHandler(ctx *gin.Context) {
+	log.Info("received request to delete habit template bundle")
+	bundleId, err := uuid.Parse(ctx.Param("bundleId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid bundle ID %s", ctx.Param("bundleId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid bundle ID"})
+		return
+	}
+
+	if err := persistence.DeleteHabitTemplateBundle(bundleId); err != nil {
+		log.Error(fmt.Errorf("unable to delete habit template bundle: %+v", err))
+		switch err {
+		case ErrBundleDoesNotExist:
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"http_code": http.StatusNotFound, "success": false,
+				"message": "Cannot find bundle"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted bundle"})
+}
+
+// function used to retrieve completion insights for all user habits
+func getInsightsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve habit insights")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	insights, err := getUserInsights(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit insights: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "insights": insights})
+}
+
+// function used to abort requests with errors raised
+// while processing goals and promoted TODO items
+func abortWithGoalError(ctx *gin.Context, err error) {
+	switch err {
+	case ErrGoalDoesNotExist:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"http_code": http.StatusNotFound, "success": false,
+			"message": "Cannot find goal"})
+	case ErrHabitDoesNotExist:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"http_code": http.StatusNotFound, "success": false,
+			"message": "Cannot find habit"})
+	case ErrTodoItemDoesNotExist:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"http_code": http.StatusNotFound, "success": false,
+			"message": "Cannot find TODO item"})
+	case ErrTodoItemPermissionDenied:
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"http_code": http.StatusForbidden, "success": false,
+			"message": "Insufficient permissions for TODO item"})
+	case ErrTodoItemAlreadyPromoted:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"http_code": http.StatusConflict, "success": false,
+			"message": "TODO item has already been promoted"})
+	case ErrInvalidGoal:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid goal"})
+	default:
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"http_code": http.StatusInternalServerError, "success": false,
+			"message": "Internal server error"})
+	}
+}
+
+// function used to parse goal ID from request path
+func parseGoalId(ctx *gin.Context) (uuid.UUID, bool) {
+	goalId, err := uuid.Parse(ctx.Param("goalId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid goal ID %s", ctx.Param("goalId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid goal ID"})
+		return goalId, false
+	}
+	return goalId, true
+}
+
+// function used to retrieve all goals of a user with progress
+func getGoalsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve goals")
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+
+	goals, err := getUserGoals(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve goals: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "goals": goals})
+}
+
+// function used to create a new goal for a user
+func createGoalHandler(ctx *gin.Context) {
+	log.Info("received request to create goal")
+	var request Goal
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+	if err := validateGoal(request); err != nil {
+		abortWithGoalError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	goalId, err := persistence.CreateGoal(uid, request)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create goal for user %s: %+v", uid, err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "goal_id": goalId})
+}
+
+// function used to retrieve a goal along with its linked
+// habits and TODO items and its progress
+func getGoalHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve goal")
+	goalId, ok := parseGoalId(ctx)
+	if !ok {
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	goal, err := getGoal(uid, goalId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve goal: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "goal": goal})
+}
+
+// function used to update the details of a goal
+func updateGoalHandler(ctx *gin.Context) {
+	log.Info("received request to update goal")
+	goalId, ok := parseGoalId(ctx)
+	if !ok {
+		return
+	}
+	var request Goal
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+	if err := validateGoal(request); err != nil {
+		abortWithGoalError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.UpdateGoal(uid, goalId, request); err != nil {
+		log.Error(fmt.Errorf("unable to update goal: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated goal"})
+}
+
+// function used to delete a goal. linked habits and
+// TODO items are not deleted along with the goal
+func deleteGoalHandler(ctx *gin.Context) {
+	log.Info("received request to delete goal")
+	goalId, ok := parseGoalId(ctx)
+	if !ok {
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteGoal(uid, goalId); err != nil {
+		log.Error(fmt.Errorf("unable to delete goal: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted goal"})
+}
+
+// function used to link a habit to a goal
+func linkGoalHabitHandler(ctx *gin.Context) {
+	log.Info("received request to link habit to goal")
+	setGoalHabit(ctx, true)
+}
+
+// function used to unlink a habit from a goal
+func unlinkGoalHabitHandler(ctx *gin.Context) {
+	log.Info("received request to unlink habit from goal")
+	setGoalHabit(ctx, false)
+}
+
+// function used to link or unlink a habit to a goal
+func setGoalHabit(ctx *gin.Context, linked bool) {
+	goalId, ok := parseGoalId(ctx)
+	if !ok {
+		return
+	}
+	habitId, err := uuid.Parse(ctx.Param("habitId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid habit ID %s", ctx.Param("habitId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetGoalHabit(uid, goalId, habitId, linked); err != nil {
+		log.Error(fmt.Errorf("unable to update goal habit: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated goal"})
+}
+
+// function used to link a TODO item to a goal
+func linkGoalTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to link TODO item to goal")
+	setGoalTodoItem(ctx, true)
+}
+
+// function used to unlink a TODO item from a goal
+func unlinkGoalTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to unlink TODO item from goal")
+	setGoalTodoItem(ctx, false)
+}
+
+// function used to link or unlink a TODO item to a goal
+func setGoalTodoItem(ctx *gin.Context, linked bool) {
+	goalId, ok := parseGoalId(ctx)
+	if !ok {
+		return
+	}
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid item ID %s", ctx.Param("itemId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid item ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := linkGoalTodoItem(uid, goalId, itemId, linked); err != nil {
+		log.Error(fmt.Errorf("unable to update goal TODO item: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated goal"})
+}
+
+// function used to promote a TODO item into a recurring habit.
+// the habit takes its name (and by default its description)
+// from the item, and the item is marked as completed
+func promoteTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to promote TODO item to habit")
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Sprintf("received invalid item ID %s", ctx.Param("itemId")))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid item ID"})
+		return
+	}
+	var request struct {
+		HabitDescription string  `json:"habit_description"`
+		HabitCycle       string  `json:"habit_cycle" binding:"required"`
+		HabitTarget      float64 `json:"habit_target"`
+		HabitUnit        string  `json:"habit_unit"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error("received invalid request body")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid request body"})
+		return
+	}
+
+	// check that given habit cycle and target are valid
+	if !isValidCycle(request.HabitCycle) {
+		log.Error(fmt.Sprintf("received invalid cycle %s", request.HabitCycle))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit cycle"})
+		return
+	}
+	if request.HabitTarget < 0 {
+		log.Error(fmt.Sprintf("received invalid target %f", request.HabitTarget))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"http_code": http.StatusBadRequest, "success": false,
+			"message": "Invalid habit target"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	habitId, err := promoteTodoItem(uid, itemId, Habit{
+		HabitDescription: request.HabitDescription,
+		HabitCycle:       request.HabitCycle,
+		HabitTarget:      request.HabitTarget,
+		HabitUnit:        request.HabitUnit,
+	})
+	if err != nil {
+		log.Error(fmt.Errorf("unable to promote TODO item: %+v", err))
+		abortWithGoalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "habit_id": habitId})
 }
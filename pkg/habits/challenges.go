@@ -0,0 +1,153 @@
+package habits
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+var (
+	// define custom errors
+	ErrInvalidChallenge = errors.New("Invalid challenge")
+)
+
+const (
+	// define maximum duration of a challenge in days
+	maxChallengeDays = 365
+)
+
+type LeaderboardEntry struct {
+	Rank           int       `json:"rank"`
+	Uid            string    `json:"uid"`
+	HabitId        uuid.UUID `json:"habit_id"`
+	CompletedDays  int       `json:"completed_days"`
+	DueDays        int       `json:"due_days"`
+	CompletionRate float64   `json:"completion_rate"`
+	Streak         int64     `json:"streak"`
+	LongestStreak  int64     `json:"longest_streak"`
+}
+
+// function used to validate a new challenge and evaluate its start
+// and end dates. challenges start today unless a start date is given
+// and end (exclusively) after the given number of days
+func prepareChallenge(challenge Challenge) (Challenge, error) {
+	if !isValidCycle(challenge.HabitCycle) || challenge.HabitTarget < 0 {
+		return challenge, ErrInvalidChallenge
+	}
+	if challenge.DurationDays < 1 || challenge.DurationDays > maxChallengeDays {
+		return challenge, ErrInvalidChallenge
+	}
+
+	start := clock.Now()
+	if len(challenge.StartDate) > 0 {
+		parsed, err := time.Parse(excusalDateFormat, challenge.StartDate)
+		if err != nil {
+			return challenge, ErrInvalidChallenge
+		}
+		start = parsed
+	}
+	year, month, day := start.Date()
+	start = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	challenge.StartDate = start.Format(excusalDateFormat)
+	challenge.EndDate = start.Add(time.Hour * 24 * time.Duration(challenge.DurationDays)).Format(excusalDateFormat)
+	return challenge, nil
+}
+
+// function used to join a challenge with given challenge ID. users
+// can join any challenge that has not yet ended
+func joinChallenge(uid string, challengeId uuid.UUID) error {
+	challenge, err := persistence.GetChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if clock.Now().Format(excusalDateFormat) >= challenge.EndDate {
+		log.Warn(fmt.Sprintf("cannot join challenge %s: challenge has ended", challengeId))
+		return ErrChallengeEnded
+	}
+	return persistence.JoinChallenge(uid, challenge)
+}
+
+// function used to count the number of days on which a habit with the
+// given cycle is due between two dates (inclusive). excused days are
+// treated as neutral and are not counted
+func countDueDays(cycle string, excused []string, from, to time.Time) int {
+	days := strings.Split(cycle, ",")
+	count := 0
+	for ts := from; !ts.After(to); ts = ts.Add(time.Hour * 24) {
+		if !utils.StringSliceContains(days, utils.ReverseCycleMappings[int(ts.Weekday())]) {
+			continue
+		}
+		if utils.StringSliceContains(excused, ts.Format(excusalDateFormat)) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// function used to generate the leaderboard for a challenge. participants
+// are ranked by completion rate over the elapsed challenge period, with
+// ties broken by current streak
+func getChallengeLeaderboard(uid string, challengeId uuid.UUID) ([]LeaderboardEntry, error) {
+	leaderboard := []LeaderboardEntry{}
+	challenge, err := persistence.GetChallenge(challengeId)
+	if err != nil {
+		return leaderboard, err
+	}
+	participants, err := persistence.GetChallengeParticipants(uid, challenge)
+	if err != nil {
+		return leaderboard, err
+	}
+
+	// evaluate elapsed period of challenge
+	start, _ := time.Parse(excusalDateFormat, challenge.StartDate)
+	end, _ := time.Parse(excusalDateFormat, challenge.EndDate)
+	year, month, day := clock.Now().Date()
+	to := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if !to.Before(end) {
+		to = end.Add(-time.Hour * 24)
+	}
+
+	for _, participant := range participants {
+		// count distinct days with completions during challenge
+		completed := []string{}
+		for _, date := range participant.CompletionDates {
+			if !utils.StringSliceContains(completed, date) {
+				completed = append(completed, date)
+			}
+		}
+		entry := LeaderboardEntry{
+			Uid:           participant.Uid,
+			HabitId:       participant.HabitId,
+			CompletedDays: len(completed),
+			DueDays:       countDueDays(challenge.HabitCycle, participant.ExcusedDays, start, to),
+			Streak:        participant.Streak,
+			LongestStreak: participant.LongestStreak,
+		}
+		if entry.DueDays > 0 {
+			entry.CompletionRate = float64(entry.CompletedDays) / float64(entry.DueDays)
+			if entry.CompletionRate > 1 {
+				entry.CompletionRate = 1
+			}
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		if leaderboard[i].CompletionRate != leaderboard[j].CompletionRate {
+			return leaderboard[i].CompletionRate > leaderboard[j].CompletionRate
+		}
+		return leaderboard[i].Streak > leaderboard[j].Streak
+	})
+	for i := range leaderboard {
+		leaderboard[i].Rank = i + 1
+	}
+	return leaderboard, nil
+}
@@ -0,0 +1,31 @@
+package habits
+
+import (
+	"time"
+)
+
+// Clock is used to retrieve the current time. all habit logic and
+// persistence reads the current time from the package clock so that
+// the passing of days can be simulated
+type Clock interface {
+	Now() time.Time
+}
+
+// define clock that returns the current system time in UTC
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// define clock used across the habits package
+var clock Clock = systemClock{}
+
+// function used to set the clock used by the habits package.
+// the system clock is restored if no clock is given
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clock = c
+}
@@ -0,0 +1,93 @@
+package habits
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// function used to create a router serving the excusal handlers
+// for a user located in the given timezone
+func newExcusalTestRouter(t *testing.T, loc *time.Location, now time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	SetClock(&fakeClock{now})
+	userLocation = func(string) (*time.Location, error) {
+		return loc, nil
+	}
+	t.Cleanup(func() {
+		SetClock(nil)
+		userLocation = getUserLocation
+	})
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Set("uid", "test-user")
+	})
+	router.POST("/habits/skip/:habitId", skipHabitDaysHandler)
+	router.POST("/habits/vacation", vacationHandler)
+	return router
+}
+
+func TestExcusalsRejectPastDates(t *testing.T) {
+	// 2024-01-02 03:00 UTC is still 2024-01-01 in new york
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %+v", err)
+	}
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		path string
+		body string
+	}{
+		{"skip yesterday", time.UTC, "/habits/skip/1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+			`{"dates": ["2024-01-01"]}`},
+		{"skip includes past date", time.UTC, "/habits/skip/1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+			`{"dates": ["2024-01-03", "2023-12-31"]}`},
+		{"skip yesterday in user timezone", newYork, "/habits/skip/1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+			`{"dates": ["2023-12-31"]}`},
+		{"vacation starting yesterday", time.UTC, "/habits/vacation",
+			`{"start": "2024-01-01", "end": "2024-01-05"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newExcusalTestRouter(t, test.loc, now)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			request.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+			}
+			if !strings.Contains(recorder.Body.String(), "Dates must not be in the past") {
+				t.Errorf("unexpected response body %s", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestExcusalDatesUseUserTimezone(t *testing.T) {
+	// 2024-01-02 03:00 UTC is still 2024-01-01 in new york, so
+	// 2024-01-01 is a valid skip day for users in new york only
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %+v", err)
+	}
+	if _, err := parseExcusalDates([]string{"2024-01-01"}, now.In(newYork).Format(excusalDateFormat)); err != nil {
+		t.Errorf("expected current day in user timezone to be accepted: %+v", err)
+	}
+	if _, err := parseExcusalDates([]string{"2024-01-01"}, now.Format(excusalDateFormat)); err != ErrPastExcusalDate {
+		t.Errorf("expected %+v, got %+v", ErrPastExcusalDate, err)
+	}
+	if _, err := expandDateRange("2024-01-01", "2024-01-03", now.In(newYork).Format(excusalDateFormat)); err != nil {
+		t.Errorf("expected vacation starting today to be accepted: %+v", err)
+	}
+}
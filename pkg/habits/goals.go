@@ -0,0 +1,166 @@
+package habits
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+	api "github.com/PSauerborn/lifelink/pkg/utils/accessors"
+)
+
+var (
+	// define custom errors
+	ErrInvalidGoal = errors.New("Invalid goal")
+)
+
+// function used to validate a goal. target dates are optional
+// but must be given in the format used for habit dates
+func validateGoal(goal Goal) error {
+	if len(goal.GoalName) == 0 {
+		return ErrInvalidGoal
+	}
+	if goal.TargetDate != nil {
+		if _, err := time.Parse(excusalDateFormat, *goal.TargetDate); err != nil {
+			log.Error(fmt.Sprintf("received invalid goal target date %s", *goal.TargetDate))
+			return ErrInvalidGoal
+		}
+	}
+	return nil
+}
+
+// function used to evaluate the completion rate of a habit linked to
+// a goal. the rate is evaluated as the share of due days completed
+// since the later of goal and habit creation, capped at 1
+func getGoalHabitCompletionRate(habit GoalHabit, since time.Time, now time.Time) float64 {
+	if habit.habit.Created.After(since) {
+		since = habit.habit.Created
+	}
+	year, month, day := since.UTC().Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = now.UTC().Date()
+	to := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	// count distinct days with completions during window
+	completed := []string{}
+	for _, ts := range habit.completions {
+		date := ts.UTC().Format(excusalDateFormat)
+		if ts.Before(from) || utils.StringSliceContains(completed, date) {
+			continue
+		}
+		completed = append(completed, date)
+	}
+	dueDays := countDueDays(habit.HabitCycle, habit.habit.ExcusedDays, from, to)
+	if dueDays == 0 {
+		return 0
+	}
+	rate := float64(len(completed)) / float64(dueDays)
+	if rate > 1 {
+		rate = 1
+	}
+	return rate
+}
+
+// function used to evaluate the progress of a goal. each linked
+// habit contributes its completion rate and each linked TODO item
+// contributes 1 once completed. overall progress is the average
+// over all linked habits and items
+func evaluateGoalProgress(goal Goal, now time.Time) Goal {
+	var habitTotal, itemTotal float64
+	for i, habit := range goal.Habits {
+		goal.Habits[i].Status = getHabitStatusAt(habit.habit, now)
+		goal.Habits[i].CompletionRate = getGoalHabitCompletionRate(habit, goal.Created, now)
+		habitTotal += goal.Habits[i].CompletionRate
+	}
+	for _, item := range goal.Items {
+		if item.Completed {
+			itemTotal++
+		}
+	}
+	if len(goal.Habits) > 0 {
+		goal.HabitProgress = habitTotal / float64(len(goal.Habits))
+	}
+	if len(goal.Items) > 0 {
+		goal.ItemProgress = itemTotal / float64(len(goal.Items))
+	}
+	if components := len(goal.Habits) + len(goal.Items); components > 0 {
+		goal.Progress = (habitTotal + itemTotal) / float64(components)
+	}
+	return goal
+}
+
+// function used to retrieve all goals of a user with progress
+func getUserGoals(uid string) ([]Goal, error) {
+	goals, err := persistence.GetUserGoals(uid)
+	if err != nil {
+		return goals, err
+	}
+	now := clock.Now()
+	for i := range goals {
+		goals[i] = evaluateGoalProgress(goals[i], now)
+	}
+	return goals, nil
+}
+
+// function used to retrieve a single goal of a user with progress
+func getGoal(uid string, goalId uuid.UUID) (Goal, error) {
+	goal, err := persistence.GetGoal(uid, goalId)
+	if err != nil {
+		return goal, err
+	}
+	return evaluateGoalProgress(goal, clock.Now()), nil
+}
+
+// function used to convert errors returned by the TODO API
+// into the errors used by the habits API
+func getTodoAPIError(err error) error {
+	switch err {
+	case api.ErrTodoItemDoesNotExist:
+		return ErrTodoItemDoesNotExist
+	case api.ErrTodoPermissionDenied:
+		return ErrTodoItemPermissionDenied
+	case api.ErrTodoItemPromoted:
+		return ErrTodoItemAlreadyPromoted
+	default:
+		return err
+	}
+}
+
+// function used to link (or unlink) a TODO item to a goal. items
+// can be linked by any user with read access to the item, while
+// items can always be unlinked from goals owned by the user
+func linkGoalTodoItem(uid string, goalId, itemId uuid.UUID, linked bool) error {
+	if linked {
+		if err := todoAPIAccessor.CheckTodoItem(uid, itemId); err != nil {
+			return getTodoAPIError(err)
+		}
+	}
+	return persistence.SetGoalTodoItem(uid, goalId, itemId, linked)
+}
+
+// function used to promote a TODO item into a recurring habit. the
+// item is first marked as promoted through the TODO API (which checks
+// that the user can edit the item and that the item has not already
+// been promoted). the promotion is released if the habit cannot be
+// created, and the item is completed once the habit has been created
+func promoteTodoItem(uid string, itemId uuid.UUID, habit Habit) (uuid.UUID, error) {
+	if err := todoAPIAccessor.PromoteTodoItem(uid, itemId); err != nil {
+		return uuid.Nil, getTodoAPIError(err)
+	}
+	habitId, err := persistence.PromoteTodoItem(uid, itemId, habit)
+	if err != nil {
+		if err := todoAPIAccessor.ReleaseTodoItemPromotion(uid, itemId); err != nil {
+			log.Error(fmt.Errorf("unable to release promotion of TODO item %s: %+v", itemId, err))
+		}
+		return uuid.Nil, err
+	}
+	// the habit is kept if the item cannot be completed, since
+	// the item can still be completed through the TODO API
+	if err := todoAPIAccessor.CompleteTodoItem(uid, itemId); err != nil {
+		log.Error(fmt.Errorf("unable to complete promoted TODO item %s: %+v", itemId, err))
+	}
+	return habitId, nil
+}
@@ -0,0 +1,252 @@
+package habits
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+const (
+	// define thresholds used to detect days on which a habit is
+	// chronically missed. a weekday must have been due a minimum
+	// number of times before suggestions are made
+	missedDaySuccessRate = 0.4
+	missedDayMinimumDue  = 4
+)
+
+type WeekdaySuccess struct {
+	Due         int     `json:"due"`
+	Completed   int     `json:"completed"`
+	SuccessRate float64 `json:"success_rate"`
+}
+
+type HabitInsights struct {
+	HabitId            uuid.UUID                 `json:"habit_id"`
+	HabitName          string                    `json:"habit_name"`
+	Completions        int                       `json:"completions"`
+	HourlyDistribution []int                     `json:"hourly_distribution"`
+	PeriodDistribution map[string]int            `json:"period_distribution"`
+	BestPeriod         string                    `json:"best_period"`
+	WeekdaySuccess     map[string]WeekdaySuccess `json:"weekday_success"`
+}
+
+type HabitCorrelation struct {
+	HabitId         uuid.UUID `json:"habit_id"`
+	HabitName       string    `json:"habit_name"`
+	OtherHabitId    uuid.UUID `json:"other_habit_id"`
+	OtherHabitName  string    `json:"other_habit_name"`
+	CoCompletedDays int64     `json:"co_completed_days"`
+	Correlation     float64   `json:"correlation"`
+}
+
+type ScheduleSuggestion struct {
+	HabitId     uuid.UUID `json:"habit_id"`
+	HabitName   string    `json:"habit_name"`
+	Weekday     string    `json:"weekday"`
+	SuccessRate float64   `json:"success_rate"`
+	Alternative *string   `json:"alternative"`
+	Suggestion  string    `json:"suggestion"`
+}
+
+type UserInsights struct {
+	Timezone     string               `json:"timezone"`
+	Habits       []HabitInsights      `json:"habits"`
+	Correlations []HabitCorrelation   `json:"correlations"`
+	Suggestions  []ScheduleSuggestion `json:"suggestions"`
+}
+
+// function used to map an hour of the day onto a period of the day
+func getDayPeriod(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// function used to evaluate insights for a single habit. completion
+// times are evaluated in the local time of the user, while weekday
+// success rates use the same (UTC) days used to evaluate due dates
+func getHabitInsights(history HabitHistory, loc *time.Location, now time.Time) HabitInsights {
+	habit := history.Habit
+	insights := HabitInsights{
+		HabitId:            habit.HabitId,
+		HabitName:          habit.HabitName,
+		Completions:        len(history.Completions),
+		HourlyDistribution: make([]int, 24),
+		PeriodDistribution: map[string]int{"morning": 0, "afternoon": 0, "evening": 0, "night": 0},
+		WeekdaySuccess:     map[string]WeekdaySuccess{},
+	}
+
+	completedDays := []string{}
+	for _, ts := range history.Completions {
+		local := ts.In(loc)
+		insights.HourlyDistribution[local.Hour()]++
+		insights.PeriodDistribution[getDayPeriod(local.Hour())]++
+		completedDays = append(completedDays, ts.UTC().Format(excusalDateFormat))
+	}
+	for period, count := range insights.PeriodDistribution {
+		if count > 0 && (len(insights.BestPeriod) == 0 || count > insights.PeriodDistribution[insights.BestPeriod]) {
+			insights.BestPeriod = period
+		}
+	}
+
+	// evaluate success rate for each day in habit cycle up to
+	// (but excluding) the current day, which is still in progress
+	cycle := strings.Split(habit.HabitCycle, ",")
+	for _, day := range cycle {
+		insights.WeekdaySuccess[day] = WeekdaySuccess{}
+	}
+	year, month, day := habit.Created.UTC().Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = now.UTC().Date()
+	end := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for ts := start; ts.Before(end); ts = ts.Add(time.Hour * 24) {
+		weekday := utils.ReverseCycleMappings[int(ts.Weekday())]
+		if !utils.StringSliceContains(cycle, weekday) || habitDayExcused(habit, ts) {
+			continue
+		}
+		success := insights.WeekdaySuccess[weekday]
+		success.Due++
+		if utils.StringSliceContains(completedDays, ts.Format(excusalDateFormat)) {
+			success.Completed++
+		}
+		insights.WeekdaySuccess[weekday] = success
+	}
+	for weekday, success := range insights.WeekdaySuccess {
+		if success.Due > 0 {
+			success.SuccessRate = float64(success.Completed) / float64(success.Due)
+			insights.WeekdaySuccess[weekday] = success
+		}
+	}
+	return insights
+}
+
+// function used to generate schedule suggestions for a habit. weekdays
+// with a low success rate are flagged, and the weekday outside of the
+// cycle on which the habit is most often completed anyway is suggested
+// as an alternative
+func getScheduleSuggestions(history HabitHistory, insights HabitInsights) []ScheduleSuggestion {
+	suggestions := []ScheduleSuggestion{}
+	cycle := strings.Split(history.Habit.HabitCycle, ",")
+
+	// count completions on days outside of habit cycle
+	offCycle := map[string]int{}
+	for _, ts := range history.Completions {
+		weekday := utils.ReverseCycleMappings[int(ts.UTC().Weekday())]
+		if !utils.StringSliceContains(cycle, weekday) {
+			offCycle[weekday]++
+		}
+	}
+	var alternative *string
+	for _, weekday := range utils.ValidCycles {
+		if count, ok := offCycle[weekday]; ok && (alternative == nil || count > offCycle[*alternative]) {
+			day := weekday
+			alternative = &day
+		}
+	}
+
+	for _, weekday := range utils.ValidCycles {
+		success, ok := insights.WeekdaySuccess[weekday]
+		if !ok || success.Due < missedDayMinimumDue || success.SuccessRate >= missedDaySuccessRate {
+			continue
+		}
+		suggestion := ScheduleSuggestion{
+			HabitId:     history.Habit.HabitId,
+			HabitName:   history.Habit.HabitName,
+			Weekday:     weekday,
+			SuccessRate: success.SuccessRate,
+			Alternative: alternative,
+		}
+		if alternative != nil {
+			suggestion.Suggestion = fmt.Sprintf("Consider moving %s from %s to %s",
+				history.Habit.HabitName, weekday, *alternative)
+		} else {
+			suggestion.Suggestion = fmt.Sprintf("Consider removing %s from the cycle of %s",
+				weekday, history.Habit.HabitName)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+// function used to generate insights for all habits owned by a user.
+// habit correlations are evaluated as the jaccard index of the days
+// on which both habits were completed
+func getUserInsights(uid string) (UserInsights, error) {
+	insights := UserInsights{
+		Habits:       []HabitInsights{},
+		Correlations: []HabitCorrelation{},
+		Suggestions:  []ScheduleSuggestion{},
+	}
+	loc, err := userLocation(uid)
+	if err != nil {
+		return insights, err
+	}
+	insights.Timezone = loc.String()
+
+	history, err := persistence.GetUserHabitHistory(uid)
+	if err != nil {
+		return insights, err
+	}
+	pairs, err := persistence.GetHabitCoCompletions(uid)
+	if err != nil {
+		return insights, err
+	}
+
+	now := clock.Now()
+	names := map[uuid.UUID]string{}
+	completedDays := map[uuid.UUID]int{}
+	for _, habitHistory := range history {
+		habitInsights := getHabitInsights(habitHistory, loc, now)
+		insights.Habits = append(insights.Habits, habitInsights)
+		insights.Suggestions = append(insights.Suggestions,
+			getScheduleSuggestions(habitHistory, habitInsights)...)
+
+		// count distinct days with completions for correlations
+		days := []string{}
+		for _, ts := range habitHistory.Completions {
+			if day := ts.UTC().Format(excusalDateFormat); !utils.StringSliceContains(days, day) {
+				days = append(days, day)
+			}
+		}
+		names[habitHistory.Habit.HabitId] = habitHistory.Habit.HabitName
+		completedDays[habitHistory.Habit.HabitId] = len(days)
+	}
+
+	for _, pair := range pairs {
+		// skip pairs including archived habits
+		if _, ok := names[pair.HabitId]; !ok {
+			continue
+		}
+		if _, ok := names[pair.OtherHabitId]; !ok {
+			continue
+		}
+		union := int64(completedDays[pair.HabitId]+completedDays[pair.OtherHabitId]) - pair.CoCompletedDays
+		correlation := HabitCorrelation{
+			HabitId:         pair.HabitId,
+			HabitName:       names[pair.HabitId],
+			OtherHabitId:    pair.OtherHabitId,
+			OtherHabitName:  names[pair.OtherHabitId],
+			CoCompletedDays: pair.CoCompletedDays,
+		}
+		if union > 0 {
+			correlation.Correlation = float64(pair.CoCompletedDays) / float64(union)
+		}
+		insights.Correlations = append(insights.Correlations, correlation)
+	}
+	sort.SliceStable(insights.Correlations, func(i, j int) bool {
+		return insights.Correlations[i].Correlation > insights.Correlations[j].Correlation
+	})
+	return insights, nil
+}
@@ -0,0 +1,35 @@
+package habits
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// middleware used to protect routes with admin-only
+// access. must be used after user injection middleware
+func AdminProtected() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// get user ID from context and get admin access
+		uid := ctx.MustGet("uid").(string)
+		admin, err := persistence.IsAdminUser(uid)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to check admin status for user: %+v", err))
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"http_code": http.StatusInternalServerError, "success": false,
+				"message": "Internal server error"})
+			return
+		}
+
+		// return 403 if user does not have admin rights
+		if !admin {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"http_code": http.StatusForbidden, "success": false,
+				"message": "Forbidden"})
+			return
+		}
+		ctx.Next()
+	}
+}
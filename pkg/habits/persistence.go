@@ -17,6 +17,26 @@ var (
 	ErrInvalidModule     = errors.New("module does not exist")
 	ErrModuleExists      = errors.New("module already exists")
 	ErrHabitDoesNotExist = errors.New("habit does not exist")
+	ErrExcusalNotFound   = errors.New("excusal does not exist")
+
+	ErrPartnerDoesNotExist   = errors.New("partner does not exist")
+	ErrInvalidPartner        = errors.New("invalid accountability partner")
+	ErrInvitationNotFound    = errors.New("invitation does not exist")
+	ErrPartnerAlreadyInvited = errors.New("partner already invited")
+
+	ErrChallengeDoesNotExist = errors.New("challenge does not exist")
+	ErrChallengeEnded        = errors.New("challenge has ended")
+	ErrAlreadyParticipant    = errors.New("user already participates in challenge")
+	ErrNotParticipant        = errors.New("user does not participate in challenge")
+
+	ErrTemplateDoesNotExist = errors.New("template does not exist")
+	ErrBundleDoesNotExist   = errors.New("template bundle does not exist")
+
+	ErrGoalDoesNotExist     = errors.New("goal does not exist")
+	ErrTodoItemDoesNotExist = errors.New("TODO item does not exist")
+
+	ErrTodoItemPermissionDenied = errors.New("insufficient permissions for TODO item")
+	ErrTodoItemAlreadyPromoted  = errors.New("TODO item already promoted")
 )
 
 type GraphPersistence struct {
@@ -46,7 +66,15 @@ type Habit struct {
 	LastCompleted    *time.Time `json:"last_completed"`
 	Status           string     `json:"status"`
 	Streak           int64      `json:"streak"`
+	LongestStreak    int64      `json:"longest_streak"`
 	Completions      int64      `json:"completions"`
+	Freezes          int64      `json:"freezes"`
+	ExcusedDays      []string   `json:"excused_days"`
+	HabitTarget      float64    `json:"habit_target"`
+	HabitUnit        string     `json:"habit_unit"`
+	Progress         float64    `json:"progress"`
+	Archived         bool       `json:"archived"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
 }
 
 type HabitCompletion struct {
@@ -55,8 +83,42 @@ type HabitCompletion struct {
 	EventTimestamp time.Time `json:"event_timestamp"`
 }
 
-// function used to retrieve all habits for given user
-func (db *GraphPersistence) GetUserHabits(user string) ([]Habit, error) {
+type HabitExcusal struct {
+	ExcusalId   uuid.UUID `json:"excusal_id"`
+	ExcusalDate string    `json:"excusal_date"`
+	ExcusalType string    `json:"excusal_type"`
+	Created     time.Time `json:"created"`
+}
+
+type HabitProgress struct {
+	ProgressId     uuid.UUID `json:"progress_id"`
+	ProgressDate   string    `json:"progress_date"`
+	Amount         float64   `json:"amount"`
+	EventTimestamp time.Time `json:"event_timestamp"`
+}
+
+type HabitDailyTotal struct {
+	ProgressDate string  `json:"progress_date"`
+	Total        float64 `json:"total"`
+}
+
+// function used to convert a list of strings (excused
+// dates etc) returned from the graph into a string slice
+func parseExcusedDays(value interface{}) []string {
+	values := []string{}
+	if value == nil {
+		return values
+	}
+	for _, v := range value.([]interface{}) {
+		values = append(values, v.(string))
+	}
+	return values
+}
+
+// function used to retrieve all habits for given user. habits
+// in the trash are never returned and archived habits are only
+// returned if explicitly requested
+func (db *GraphPersistence) GetUserHabits(user string, includeArchived bool) ([]Habit, error) {
 	log.Debug(fmt.Sprintf("retrieving all habits for user %s...", user))
 	habits := []Habit{}
 
@@ -65,13 +127,21 @@ func (db *GraphPersistence) GetUserHabits(user string) ([]Habit, error) {
 	defer session.Close()
 	// generate config metadata for query
 	cfg := map[string]interface{}{
-		"uid": user,
+		"uid":              user,
+		"today":            clock.Now().Format(excusalDateFormat),
+		"include_archived": includeArchived,
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
 		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)
+        WHERE h.deleted_at IS NULL AND ($include_archived OR NOT coalesce(h.archived, false))
         OPTIONAL MATCH (h)-[:OWNS]->(c:HabitCompletion)
+        OPTIONAL MATCH (h)-[:OWNS]->(e:HabitExcusal)
         RETURN h.habit_name, h.habit_id, h.habit_description,
-        h.habit_cycle, h.created, h.last_completed, h.streak, COUNT(c)`
+        h.habit_cycle, h.created, h.last_completed, h.streak, COUNT(DISTINCT c),
+        coalesce(h.freezes, 0), COLLECT(DISTINCT e.excusal_date),
+        coalesce(h.habit_target, 0.0), coalesce(h.habit_unit, ''),
+        reduce(total = 0.0, amount IN [(h)-[:OWNS]->(p:HabitProgress {progress_date: $today}) | p.amount] | total + amount),
+        coalesce(h.archived, false), coalesce(h.longest_streak, h.streak)`
 		return neo4j.Collect(tx.Run(query, cfg))
 	}
 	// get all habits from graph using persistence session
@@ -93,6 +163,13 @@ func (db *GraphPersistence) GetUserHabits(user string) ([]Habit, error) {
 			Created:          node.Values[4].(time.Time),
 			Streak:           node.Values[6].(int64),
 			Completions:      node.Values[7].(int64),
+			Freezes:          node.Values[8].(int64),
+			ExcusedDays:      parseExcusedDays(node.Values[9]),
+			HabitTarget:      node.Values[10].(float64),
+			HabitUnit:        node.Values[11].(string),
+			Progress:         node.Values[12].(float64),
+			Archived:         node.Values[13].(bool),
+			LongestStreak:    node.Values[14].(int64),
 		}
 		// add last completed date if set else leave as null
 		if lastCompleted != nil {
@@ -119,12 +196,19 @@ func (db *GraphPersistence) GetHabitByHabitId(user string, habitId uuid.UUID) (H
 	cfg := map[string]interface{}{
 		"uid":      user,
 		"habit_id": habitId.String(),
+		"today":    clock.Now().Format(excusalDateFormat),
 	}
 	// define handler function used to retrieve node data
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
 		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NULL
+        OPTIONAL MATCH (h)-[:OWNS]->(e:HabitExcusal)
         RETURN h.habit_name, h.habit_id, h.habit_description,
-        h.habit_cycle, h.created, h.last_completed, h.streak`
+        h.habit_cycle, h.created, h.last_completed, h.streak,
+        coalesce(h.freezes, 0), COLLECT(e.excusal_date),
+        coalesce(h.habit_target, 0.0), coalesce(h.habit_unit, ''),
+        reduce(total = 0.0, amount IN [(h)-[:OWNS]->(p:HabitProgress {progress_date: $today}) | p.amount] | total + amount),
+        coalesce(h.archived, false), coalesce(h.longest_streak, h.streak)`
 		results, err := tx.Run(query, cfg)
 		if err != nil {
 			log.Error(fmt.Errorf("unable to retrieve data from graph: %+v", err))
@@ -152,6 +236,13 @@ func (db *GraphPersistence) GetHabitByHabitId(user string, habitId uuid.UUID) (H
 		HabitCycle:       node.Values[3].(string),
 		Created:          node.Values[4].(time.Time),
 		Streak:           node.Values[6].(int64),
+		Freezes:          node.Values[7].(int64),
+		ExcusedDays:      parseExcusedDays(node.Values[8]),
+		HabitTarget:      node.Values[9].(float64),
+		HabitUnit:        node.Values[10].(string),
+		Progress:         node.Values[11].(float64),
+		Archived:         node.Values[12].(bool),
+		LongestStreak:    node.Values[13].(int64),
 	}
 	// parse last completed pointer if not nill
 	lastCompleted := node.Values[5]
@@ -177,7 +268,9 @@ func (db *GraphPersistence) CreateUserHabit(user string, habit Habit) error {
 		"habit_id":          uuid.New().String(),
 		"habit_description": habit.HabitDescription,
 		"habit_cycle":       orderCyclesString(habit.HabitCycle),
-		"created":           time.Now().UTC(),
+		"habit_target":      habit.HabitTarget,
+		"habit_unit":        habit.HabitUnit,
+		"created":           clock.Now(),
 		"uid":               user,
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
@@ -186,9 +279,13 @@ func (db *GraphPersistence) CreateUserHabit(user string, habit Habit) error {
             habit_id: $habit_id,
             habit_description: $habit_description,
             habit_cycle: $habit_cycle,
+            habit_target: $habit_target,
+            habit_unit: $habit_unit,
             last_completed: null,
             created: $created,
-            streak: 0
+            streak: 0,
+            longest_streak: 0,
+            freezes: 0
         })
         WITH h
         MATCH
@@ -205,9 +302,10 @@ func (db *GraphPersistence) CreateUserHabit(user string, habit Habit) error {
 	return nil
 }
 
-// function used to complete a habit with given habit ID for user
+// function used to complete a habit with given habit ID for user.
+// the completion is recorded at the given completion time
 func (db *GraphPersistence) CompleteUserHabit(user string, habitId uuid.UUID,
-	onTarget bool, streak int64) error {
+	onTarget bool, streak, freezes int64, completed time.Time) error {
 	log.Debug(fmt.Sprintf("completing habit %s for user %s...", habitId, user))
 	// create new persistence session for graph and defer closing
 	session := db.NewSession()
@@ -217,7 +315,9 @@ func (db *GraphPersistence) CompleteUserHabit(user string, habitId uuid.UUID,
 		"habit_id":  habitId.String(),
 		"uid":       user,
 		"streak":    streak,
+		"freezes":   freezes,
 		"on_target": onTarget,
+		"completed": completed,
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
 		var (
@@ -226,7 +326,9 @@ func (db *GraphPersistence) CompleteUserHabit(user string, habitId uuid.UUID,
 		)
 		// set completion date on habit as property
 		query = `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
-        SET h.last_completed = datetime(), h.streak = $streak`
+        SET h.last_completed = $completed, h.streak = $streak, h.freezes = $freezes,
+        h.longest_streak = CASE WHEN $streak > coalesce(h.longest_streak, 0)
+        THEN $streak ELSE h.longest_streak END`
 		_, err = tx.Run(query, cfg)
 		if err != nil {
 			log.Error(fmt.Errorf("unable to update habit with last completion: %+v", err))
@@ -235,7 +337,7 @@ func (db *GraphPersistence) CompleteUserHabit(user string, habitId uuid.UUID,
 
 		// create new completion node and set ownership to habbit
 		query = `CREATE (c:HabitCompletion {
-            event_timestamp: datetime(),
+            event_timestamp: $completed,
             on_target: $on_target,
             streak: $streak
         })
@@ -259,21 +361,32 @@ func (db *GraphPersistence) CompleteUserHabit(user string, habitId uuid.UUID,
 	return nil
 }
 
-// function used to complete a habit with given habit ID for user
+// function used to move a habit with given habit ID into the trash.
+// trashed habits keep their history until they are purged
 func (db *GraphPersistence) DeleteUserHabit(user string, habitId uuid.UUID) error {
-	log.Debug(fmt.Sprintf("deleting habit %s for user %s...", habitId, user))
+	log.Debug(fmt.Sprintf("moving habit %s for user %s to trash...", habitId, user))
 	// create new persitence session for graph and defer closing
 	session := db.NewSession()
 	defer session.Close()
 	// generate config metadata for query
 	cfg := map[string]interface{}{
-		"habit_id": habitId.String(),
-		"uid":      user,
+		"habit_id":   habitId.String(),
+		"uid":        user,
+		"deleted_at": clock.Now(),
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
 		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
-        DETACH DELETE h`
-		return tx.Run(query, cfg)
+        WHERE h.deleted_at IS NULL
+        SET h.deleted_at = $deleted_at
+        RETURN h.habit_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		return nil, nil
 	}
 	// get all habits from graph using persistence session
 	_, err := session.WriteTransaction(handler)
@@ -284,9 +397,215 @@ func (db *GraphPersistence) DeleteUserHabit(user string, habitId uuid.UUID) erro
 	return nil
 }
 
+// function used to restore a habit with given habit ID from the trash
+func (db *GraphPersistence) RestoreUserHabit(user string, habitId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("restoring habit %s for user %s from trash...", habitId, user))
+	// create new persitence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"habit_id": habitId.String(),
+		"uid":      user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NOT NULL
+        REMOVE h.deleted_at
+        RETURN h.habit_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to restore habit: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to archive or unarchive a habit with given habit ID.
+// archived habits keep their history but are hidden by default
+func (db *GraphPersistence) SetUserHabitArchived(user string, habitId uuid.UUID,
+	archived bool) error {
+	log.Debug(fmt.Sprintf("setting archived=%t on habit %s for user %s...", archived, habitId, user))
+	// create new persitence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"habit_id": habitId.String(),
+		"uid":      user,
+		"archived": archived,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NULL
+        SET h.archived = $archived
+        RETURN h.habit_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to archive habit: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all habits in the trash for given user
+func (db *GraphPersistence) GetTrashedHabits(user string) ([]Habit, error) {
+	log.Debug(fmt.Sprintf("retrieving trashed habits for user %s...", user))
+	habits := []Habit{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)
+        WHERE h.deleted_at IS NOT NULL
+        RETURN h.habit_name, h.habit_id, h.habit_description,
+        h.habit_cycle, h.created, h.streak, h.deleted_at
+        ORDER BY h.deleted_at DESC`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve trashed habits: %+v", err))
+		return habits, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[1].(string))
+		deletedAt := node.Values[6].(time.Time)
+		habits = append(habits, Habit{
+			HabitName:        node.Values[0].(string),
+			HabitId:          habitId,
+			HabitDescription: node.Values[2].(string),
+			HabitCycle:       node.Values[3].(string),
+			Created:          node.Values[4].(time.Time),
+			Streak:           node.Values[5].(int64),
+			DeletedAt:        &deletedAt,
+		})
+	}
+	return habits, nil
+}
+
+// function used to permanently delete a habit in the trash. all nodes
+// owned by the habit (completions, excusals, progress) are removed too
+func (db *GraphPersistence) PurgeUserHabit(user string, habitId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("purging habit %s for user %s...", habitId, user))
+	// create new persitence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"habit_id": habitId.String(),
+		"uid":      user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NOT NULL
+        OPTIONAL MATCH (h)-[:OWNS]->(n)
+        DETACH DELETE n, h
+        RETURN COUNT(DISTINCT h)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrHabitDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to purge habit: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to permanently delete all habits that were moved
+// to the trash before the given cutoff. owned nodes are removed
+// along with the habit, as are any habit nodes that have been
+// orphaned by earlier destructive deletes. the number of purged
+// habits is returned
+func (db *GraphPersistence) PurgeTrashedHabits(cutoff time.Time) (int64, error) {
+	log.Debug(fmt.Sprintf("purging habits trashed before %s...", cutoff))
+	// create new persitence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"cutoff": cutoff,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (h:Habit)
+        WHERE h.deleted_at IS NOT NULL AND h.deleted_at < $cutoff
+        OPTIONAL MATCH (h)-[:OWNS]->(n)
+        DETACH DELETE n, h
+        RETURN COUNT(DISTINCT h)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to purge trashed habits: %+v", err))
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+
+		query = `MATCH (n)
+        WHERE (n:HabitCompletion OR n:HabitExcusal OR n:HabitProgress)
+        AND NOT ()-[:OWNS]->(n)
+        DETACH DELETE n`
+		if _, err := tx.Run(query, nil); err != nil {
+			log.Error(fmt.Errorf("unable to purge orphaned habit nodes: %+v", err))
+			return nil, err
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to purge trashed habits: %+v", err))
+		return 0, err
+	}
+	return node.Values[0].(int64), nil
+}
+
+// struct used to update habits. targets and units are
+// only updated if they are given in the request
+type HabitUpdate struct {
+	HabitName        string   `json:"habit_name" binding:"required"`
+	HabitDescription string   `json:"habit_description" binding:"required"`
+	HabitCycle       string   `json:"habit_cycle" binding:"required"`
+	HabitTarget      *float64 `json:"habit_target"`
+	HabitUnit        *string  `json:"habit_unit"`
+}
+
 // function used to update a habit with given habit ID for user
 func (db *GraphPersistence) UpdateUserHabit(user string, habitId uuid.UUID,
-	habit Habit) error {
+	habit HabitUpdate) error {
 	log.Debug(fmt.Sprintf("updating habit %s for user %s...", habitId, user))
 	// create new persitence session for graph and defer closing
 	session := db.NewSession()
@@ -297,12 +616,17 @@ func (db *GraphPersistence) UpdateUserHabit(user string, habitId uuid.UUID,
 		"habit_name":        habit.HabitName,
 		"habit_description": habit.HabitDescription,
 		"habit_cycle":       orderCyclesString(habit.HabitCycle),
+		"habit_target":      habit.HabitTarget,
+		"habit_unit":        habit.HabitUnit,
 		"uid":               user,
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
 		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NULL
         SET h.habit_name = $habit_name, h.habit_description = $habit_description,
-        h.habit_cycle = $habit_cycle`
+        h.habit_cycle = $habit_cycle,
+        h.habit_target = coalesce($habit_target, h.habit_target),
+        h.habit_unit = coalesce($habit_unit, h.habit_unit)`
 		return tx.Run(query, cfg)
 	}
 	// get all habits from graph using persistence session
@@ -346,3 +670,2164 @@ func (db *GraphPersistence) GetHabitCompletions(user string, habitId uuid.UUID)
 	}
 	return completions, nil
 }
+
+// function used to retrieve all excused days for a habit
+func (db *GraphPersistence) GetHabitExcusals(user string, habitId uuid.UUID) ([]HabitExcusal, error) {
+	log.Debug(fmt.Sprintf("fetching habit excusals for habit %s...", habitId))
+	excusals := []HabitExcusal{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+	}
+	// define handler function used to retrieve node data
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})-[:OWNS]->(e:HabitExcusal)
+        RETURN e.excusal_id, e.excusal_date, e.excusal_type, e.created ORDER BY e.excusal_date DESC`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit excusals: %+v", err))
+		return excusals, err
+	}
+	for _, node := range nodes {
+		excusalId, _ := uuid.Parse(node.Values[0].(string))
+		excusals = append(excusals, HabitExcusal{
+			ExcusalId:   excusalId,
+			ExcusalDate: node.Values[1].(string),
+			ExcusalType: node.Values[2].(string),
+			Created:     node.Values[3].(time.Time),
+		})
+	}
+	return excusals, nil
+}
+
+// function used to excuse a set of days for a given habit. days
+// that have already been excused are left untouched
+func (db *GraphPersistence) AddHabitExcusals(user string, habitId uuid.UUID,
+	dates []string, excusalType string) error {
+	log.Debug(fmt.Sprintf("excusing days %+v for habit %s...", dates, habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":          user,
+		"habit_id":     habitId.String(),
+		"dates":        dates,
+		"excusal_type": excusalType,
+		"created":      clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        UNWIND $dates AS date
+        MERGE (h)-[:OWNS]->(e:HabitExcusal {excusal_date: date})
+        ON CREATE SET e.excusal_id = randomUUID(), e.excusal_type = $excusal_type,
+        e.created = $created
+        RETURN DISTINCT h.habit_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to create habit excusals: %+v", err))
+			return nil, err
+		}
+		// habit must exist for excusals to be created
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to excuse habit days: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to excuse a set of days for all habits owned
+// by a given user. used to put a user into vacation mode
+func (db *GraphPersistence) AddVacationExcusals(user string, dates []string) error {
+	log.Debug(fmt.Sprintf("setting vacation days %+v for user %s...", dates, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":     user,
+		"dates":   dates,
+		"created": clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)
+        WHERE h.deleted_at IS NULL
+        UNWIND $dates AS date
+        MERGE (h)-[:OWNS]->(e:HabitExcusal {excusal_date: date})
+        ON CREATE SET e.excusal_id = randomUUID(), e.excusal_type = 'vacation',
+        e.created = $created`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to set vacation days: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to spend a streak freeze for a habit. the
+// number of available freezes is decremented and the given
+// day is excused with a freeze excusal
+func (db *GraphPersistence) SpendHabitFreeze(user string, habitId uuid.UUID, date string) error {
+	log.Debug(fmt.Sprintf("spending streak freeze for habit %s on %s...", habitId, date))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+		"date":     date,
+		"created":  clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE coalesce(h.freezes, 0) > 0
+        SET h.freezes = h.freezes - 1
+        MERGE (h)-[:OWNS]->(e:HabitExcusal {excusal_date: $date})
+        ON CREATE SET e.excusal_id = randomUUID(), e.created = $created
+        SET e.excusal_type = 'freeze'
+        RETURN h.freezes`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to spend streak freeze: %+v", err))
+			return nil, err
+		}
+		// no results are returned if the habit does not
+		// exist or has no streak freezes available
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrNoStreakFreezes
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to spend streak freeze: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to remove an excused day from a habit. note that
+// spent streak freezes cannot be removed
+func (db *GraphPersistence) DeleteHabitExcusal(user string, habitId uuid.UUID, date string) error {
+	log.Debug(fmt.Sprintf("removing excusal on %s for habit %s...", date, habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+		"date":     date,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(:Habit {habit_id: $habit_id})-[:OWNS]->(e:HabitExcusal {excusal_date: $date})
+        WHERE e.excusal_type <> 'freeze'
+        DETACH DELETE e
+        RETURN COUNT(e)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to delete habit excusal: %+v", err))
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrExcusalNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete habit excusal: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to log progress towards the daily target of a
+// quantitative habit. the running total for the day is incremented
+// in a single statement so that concurrent updates are not lost.
+// the totals before and after the new progress entry are returned
+func (db *GraphPersistence) AddHabitProgress(user string, habitId uuid.UUID,
+	amount float64, date string) (float64, float64, error) {
+	log.Debug(fmt.Sprintf("logging progress %f for habit %s...", amount, habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":           user,
+		"habit_id":      habitId.String(),
+		"progress_id":   uuid.New().String(),
+		"progress_date": date,
+		"amount":        amount,
+		"logged":        clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        SET h.progress = CASE WHEN h.progress_date = $progress_date
+            THEN coalesce(h.progress, 0.0) ELSE 0.0 END + $amount,
+            h.progress_date = $progress_date
+        CREATE (h)-[:OWNS]->(:HabitProgress {
+            progress_id: $progress_id,
+            progress_date: $progress_date,
+            amount: $amount,
+            event_timestamp: $logged
+        })
+        RETURN h.progress - $amount, h.progress`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to log habit progress: %+v", err))
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to log habit progress: %+v", err))
+		return 0, 0, err
+	}
+	return node.Values[0].(float64), node.Values[1].(float64), nil
+}
+
+// function used to retrieve all progress entries for a habit
+func (db *GraphPersistence) GetHabitProgress(user string, habitId uuid.UUID) ([]HabitProgress, error) {
+	log.Debug(fmt.Sprintf("fetching habit progress for habit %s...", habitId))
+	entries := []HabitProgress{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+	}
+	// define handler function used to retrieve node data
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})-[:OWNS]->(p:HabitProgress)
+        RETURN p.progress_id, p.progress_date, p.amount, p.event_timestamp
+        ORDER BY p.event_timestamp DESC`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit progress: %+v", err))
+		return entries, err
+	}
+	for _, node := range nodes {
+		progressId, _ := uuid.Parse(node.Values[0].(string))
+		entries = append(entries, HabitProgress{
+			ProgressId:     progressId,
+			ProgressDate:   node.Values[1].(string),
+			Amount:         node.Values[2].(float64),
+			EventTimestamp: node.Values[3].(time.Time),
+		})
+	}
+	return entries, nil
+}
+
+// function used to retrieve the total progress logged
+// against a habit for each day that progress was made
+func (db *GraphPersistence) GetHabitDailyTotals(user string, habitId uuid.UUID) ([]HabitDailyTotal, error) {
+	log.Debug(fmt.Sprintf("fetching daily progress totals for habit %s...", habitId))
+	totals := []HabitDailyTotal{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+	}
+	// define handler function used to retrieve node data
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})-[:OWNS]->(p:HabitProgress)
+        RETURN p.progress_date, SUM(p.amount) ORDER BY p.progress_date`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit progress totals: %+v", err))
+		return totals, err
+	}
+	for _, node := range nodes {
+		totals = append(totals, HabitDailyTotal{
+			ProgressDate: node.Values[0].(string),
+			Total:        node.Values[1].(float64),
+		})
+	}
+	return totals, nil
+}
+
+type ReminderSettings struct {
+	Uid           string   `json:"uid"`
+	Timezone      string   `json:"timezone"`
+	ReminderTimes []string `json:"reminder_times" binding:"required"`
+	Enabled       bool     `json:"enabled"`
+}
+
+// function used to retrieve reminder settings for a given user.
+// users without settings receive disabled settings. the timezone
+// of the user is owned by the users module and only read here
+func (db *GraphPersistence) GetReminderSettings(user string) (ReminderSettings, error) {
+	log.Debug(fmt.Sprintf("retrieving reminder settings for user %s...", user))
+	settings := ReminderSettings{Uid: user, Timezone: "UTC", ReminderTimes: []string{}}
+	timezone, err := db.GetUserTimezone(user)
+	if err != nil {
+		return settings, err
+	}
+	settings.Timezone = timezone
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        OPTIONAL MATCH (u)-[:OWNS]->(s:HabitReminderSettings)
+        RETURN s.reminder_times, coalesce(s.enabled, false)`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve reminder settings: %+v", err))
+		return settings, err
+	}
+	if len(nodes) > 0 {
+		settings.ReminderTimes = parseExcusedDays(nodes[0].Values[0])
+		settings.Enabled = nodes[0].Values[1].(bool)
+	}
+	return settings, nil
+}
+
+// function used to retrieve reminder settings for all
+// users that currently have reminders enabled. note that
+// the timezone of the users is not included
+func (db *GraphPersistence) GetEnabledReminderSettings() ([]ReminderSettings, error) {
+	log.Debug("retrieving enabled reminder settings...")
+	settings := []ReminderSettings{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User)-[:OWNS]->(s:HabitReminderSettings {enabled: true})
+        RETURN u.uid, s.reminder_times`
+		return neo4j.Collect(tx.Run(query, nil))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve reminder settings: %+v", err))
+		return settings, err
+	}
+	for _, node := range nodes {
+		settings = append(settings, ReminderSettings{
+			Uid:           node.Values[0].(string),
+			ReminderTimes: parseExcusedDays(node.Values[1]),
+			Enabled:       true,
+		})
+	}
+	return settings, nil
+}
+
+// function used to set reminder settings for a given user. note
+// that the timezone is set with the users API and not stored here
+func (db *GraphPersistence) SetReminderSettings(user string, settings ReminderSettings) error {
+	log.Debug(fmt.Sprintf("setting reminder settings %+v for user %s...", settings, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":            user,
+		"reminder_times": settings.ReminderTimes,
+		"enabled":        settings.Enabled,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        MERGE (u)-[:OWNS]->(s:HabitReminderSettings)
+        SET s.reminder_times = $reminder_times, s.enabled = $enabled`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to set reminder settings: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve the keys of all reminders that
+// have already been delivered to a user for a given local date
+func (db *GraphPersistence) GetDeliveredReminderKeys(user, date string) ([]string, error) {
+	log.Debug(fmt.Sprintf("retrieving delivered reminders for user %s on %s...", user, date))
+	keys := []string{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":           user,
+		"reminder_date": date,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(r:HabitReminder {reminder_date: $reminder_date})
+        RETURN r.reminder_key`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve delivered reminders: %+v", err))
+		return keys, err
+	}
+	for _, node := range nodes {
+		keys = append(keys, node.Values[0].(string))
+	}
+	return keys, nil
+}
+
+// function used to record the delivery of a reminder so
+// that the same reminder is not delivered more than once
+func (db *GraphPersistence) RecordReminderDelivery(user, key, date string) error {
+	log.Debug(fmt.Sprintf("recording delivery of reminder %s for user %s...", key, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":           user,
+		"reminder_key":  key,
+		"reminder_date": date,
+		"delivered":     clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        MERGE (u)-[:OWNS]->(r:HabitReminder {reminder_key: $reminder_key})
+        ON CREATE SET r.reminder_date = $reminder_date, r.delivered = $delivered`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to record reminder delivery: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to remove delivery records for all
+// reminders delivered before the given cutoff
+func (db *GraphPersistence) PurgeDeliveredReminders(cutoff time.Time) error {
+	log.Debug(fmt.Sprintf("purging reminders delivered before %s...", cutoff))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"cutoff": cutoff,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (r:HabitReminder) WHERE r.delivered < $cutoff
+        DETACH DELETE r`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to purge delivered reminders: %+v", err))
+		return err
+	}
+	return nil
+}
+
+type HabitHistory struct {
+	Habit       Habit
+	Completions []time.Time
+}
+
+type HabitReconciliation struct {
+	HabitId       uuid.UUID
+	Status        string
+	Streak        int64
+	LongestStreak int64
+	LastCompleted *time.Time
+	// define values read from the graph before reconciling. these are
+	// compared before writing so that concurrent changes are kept
+	Previous Habit
+}
+
+// function used to retrieve a batch of habits along with their full
+// completion history. habits are ordered by habit ID and batches start
+// after the last habit ID of the previous batch, so that the graph can
+// be walked in stable batches while habits are created or deleted
+func (db *GraphPersistence) GetHabitHistoryBatch(after string, limit int) ([]HabitHistory, error) {
+	log.Debug(fmt.Sprintf("retrieving habit history batch after '%s'...", after))
+	history := []HabitHistory{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"after": after,
+		"limit": limit,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (h:Habit) WHERE h.deleted_at IS NULL AND h.habit_id > $after
+        RETURN h.habit_id, h.habit_cycle, h.created, h.streak,
+        coalesce(h.longest_streak, h.streak),
+        [(h)-[:OWNS]->(c:HabitCompletion) | c.event_timestamp],
+        [(h)-[:OWNS]->(e:HabitExcusal) | e.excusal_date],
+        h.last_completed, coalesce(h.freezes, 0)
+        ORDER BY h.habit_id LIMIT $limit`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit history: %+v", err))
+		return history, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[0].(string))
+		completions := []time.Time{}
+		for _, ts := range node.Values[5].([]interface{}) {
+			completions = append(completions, ts.(time.Time))
+		}
+		var lastCompleted *time.Time
+		if ts, ok := node.Values[7].(time.Time); ok {
+			lastCompleted = &ts
+		}
+		history = append(history, HabitHistory{
+			Habit: Habit{
+				HabitId:       habitId,
+				HabitCycle:    node.Values[1].(string),
+				Created:       node.Values[2].(time.Time),
+				Streak:        node.Values[3].(int64),
+				LongestStreak: node.Values[4].(int64),
+				ExcusedDays:   parseExcusedDays(node.Values[6]),
+				LastCompleted: lastCompleted,
+				Freezes:       node.Values[8].(int64),
+			},
+			Completions: completions,
+		})
+	}
+	return history, nil
+}
+
+// function used to write a batch of reconciled habit streaks back
+// to the graph in a single transaction. habits are only updated if
+// their streak, last completion and freezes still match the values
+// that were reconciled, so that completions or freezes used while
+// the reconciler runs are not overwritten. the number of updated
+// habits is returned
+func (db *GraphPersistence) ApplyHabitReconciliations(reconciliations []HabitReconciliation) (int, error) {
+	log.Debug(fmt.Sprintf("applying %d habit reconciliation(s)...", len(reconciliations)))
+	if len(reconciliations) == 0 {
+		return 0, nil
+	}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	updates := []map[string]interface{}{}
+	for _, r := range reconciliations {
+		update := map[string]interface{}{
+			"habit_id":       r.HabitId.String(),
+			"streak":         r.Streak,
+			"longest_streak": r.LongestStreak,
+			"last_completed": nil,
+
+			"previous_streak":         r.Previous.Streak,
+			"previous_last_completed": nil,
+			"previous_freezes":        r.Previous.Freezes,
+		}
+		if r.LastCompleted != nil {
+			update["last_completed"] = *r.LastCompleted
+		}
+		if r.Previous.LastCompleted != nil {
+			update["previous_last_completed"] = *r.Previous.LastCompleted
+		}
+		updates = append(updates, update)
+	}
+	cfg := map[string]interface{}{
+		"updates":    updates,
+		"reconciled": clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		// habits are locked (by setting the time of reconciliation)
+		// before the previous values are compared
+		query := `UNWIND $updates AS update
+        MATCH (h:Habit {habit_id: update.habit_id})
+        SET h.reconciled = $reconciled
+        WITH h, update
+        WHERE h.streak = update.previous_streak
+        AND coalesce(h.freezes, 0) = update.previous_freezes
+        AND (h.last_completed = update.previous_last_completed
+            OR (h.last_completed IS NULL AND update.previous_last_completed IS NULL))
+        SET h.streak = update.streak,
+        h.longest_streak = update.longest_streak,
+        h.last_completed = update.last_completed
+        RETURN count(h)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		return node.Values[0], nil
+	}
+	applied, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to apply habit reconciliations: %+v", err))
+		return 0, err
+	}
+	return int(applied.(int64)), nil
+}
+
+type HabitPartner struct {
+	Uid     string    `json:"uid"`
+	Status  string    `json:"status"`
+	Invited time.Time `json:"invited"`
+}
+
+type SharedHabit struct {
+	Owner            string     `json:"owner"`
+	HabitId          uuid.UUID  `json:"habit_id"`
+	HabitName        string     `json:"habit_name"`
+	HabitDescription string     `json:"habit_description"`
+	HabitCycle       string     `json:"habit_cycle"`
+	LastCompleted    *time.Time `json:"last_completed"`
+	Status           string     `json:"status"`
+	Streak           int64      `json:"streak"`
+	LongestStreak    int64      `json:"longest_streak"`
+}
+
+// function used to invite another user as an accountability partner
+// on a habit. only the owner of a habit can invite partners, and the
+// partner is stored as a pending relationship until accepted
+func (db *GraphPersistence) InviteHabitPartner(user string, habitId uuid.UUID, partner string) error {
+	log.Debug(fmt.Sprintf("inviting user %s as partner on habit %s...", partner, habitId))
+	if user == partner {
+		return ErrInvalidPartner
+	}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+		"partner":  partner,
+		"invited":  clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NULL
+        OPTIONAL MATCH (p:User {uid: $partner})
+        OPTIONAL MATCH (p)-[r:PARTNER_OF]->(h)
+        RETURN p IS NOT NULL, r IS NOT NULL`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrHabitDoesNotExist
+		}
+		if !node.Values[0].(bool) {
+			return nil, ErrPartnerDoesNotExist
+		}
+		if node.Values[1].(bool) {
+			return nil, ErrPartnerAlreadyInvited
+		}
+
+		query = `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})
+        MATCH (p:User {uid: $partner})
+        CREATE (p)-[:PARTNER_OF {status: 'pending', invited: $invited, invited_by: $uid}]->(h)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to invite habit partner: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all partners (pending and accepted)
+// for a habit. only the owner of a habit can list its partners
+func (db *GraphPersistence) GetHabitPartners(user string, habitId uuid.UUID) ([]HabitPartner, error) {
+	log.Debug(fmt.Sprintf("retrieving partners for habit %s...", habitId))
+	partners := []HabitPartner{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit {habit_id: $habit_id})<-[r:PARTNER_OF]-(p:User)
+        RETURN p.uid, r.status, r.invited ORDER BY r.invited`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit partners: %+v", err))
+		return partners, err
+	}
+	for _, node := range nodes {
+		partners = append(partners, HabitPartner{
+			Uid:     node.Values[0].(string),
+			Status:  node.Values[1].(string),
+			Invited: node.Values[2].(time.Time),
+		})
+	}
+	return partners, nil
+}
+
+// function used to remove a partner from a habit. habits owners
+// can remove any partner, and partners can remove themselves
+func (db *GraphPersistence) RemoveHabitPartner(user string, habitId uuid.UUID, partner string) error {
+	log.Debug(fmt.Sprintf("removing partner %s from habit %s...", partner, habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+		"partner":  partner,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (o:User)-[:OWNS]->(h:Habit {habit_id: $habit_id})<-[r:PARTNER_OF]-(p:User {uid: $partner})
+        WHERE o.uid = $uid OR p.uid = $uid
+        DELETE r
+        RETURN COUNT(r)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrPartnerDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to remove habit partner: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all pending partner invitations for a user
+func (db *GraphPersistence) GetPartnerInvitations(user string) ([]SharedHabit, error) {
+	log.Debug(fmt.Sprintf("retrieving partner invitations for user %s...", user))
+	invitations := []SharedHabit{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:PARTNER_OF {status: 'pending'}]->(h:Habit)<-[:OWNS]-(o:User)
+        WHERE h.deleted_at IS NULL
+        RETURN o.uid, h.habit_id, h.habit_name, h.habit_description, h.habit_cycle`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve partner invitations: %+v", err))
+		return invitations, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[1].(string))
+		invitations = append(invitations, SharedHabit{
+			Owner:            node.Values[0].(string),
+			HabitId:          habitId,
+			HabitName:        node.Values[2].(string),
+			HabitDescription: node.Values[3].(string),
+			HabitCycle:       node.Values[4].(string),
+		})
+	}
+	return invitations, nil
+}
+
+// function used to accept a pending partner invitation
+func (db *GraphPersistence) AcceptPartnerInvitation(user string, habitId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("accepting partner invitation for habit %s...", habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+		"accepted": clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[r:PARTNER_OF {status: 'pending'}]->(h:Habit {habit_id: $habit_id})
+        WHERE h.deleted_at IS NULL
+        SET r.status = 'accepted', r.accepted = $accepted
+        RETURN COUNT(r)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to accept partner invitation: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to decline a pending partner invitation. declined
+// invitations are removed so that the owner can invite again
+func (db *GraphPersistence) DeclinePartnerInvitation(user string, habitId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("declining partner invitation for habit %s...", habitId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":      user,
+		"habit_id": habitId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[r:PARTNER_OF {status: 'pending'}]->(:Habit {habit_id: $habit_id})
+        DELETE r
+        RETURN COUNT(r)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to decline partner invitation: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all habits shared with a user through
+// an accepted partnership. shared habits are returned as read-only
+// views containing the status and streak of the habit
+func (db *GraphPersistence) GetSharedHabits(user string) ([]SharedHabit, error) {
+	log.Debug(fmt.Sprintf("retrieving habits shared with user %s...", user))
+	shared := []SharedHabit{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:PARTNER_OF {status: 'accepted'}]->(h:Habit)<-[:OWNS]-(o:User)
+        WHERE h.deleted_at IS NULL AND NOT coalesce(h.archived, false)
+        RETURN o.uid, h.habit_id, h.habit_name, h.habit_description, h.habit_cycle,
+        h.created, h.last_completed, h.streak, coalesce(h.longest_streak, h.streak),
+        [(h)-[:OWNS]->(e:HabitExcusal) | e.excusal_date]`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve shared habits: %+v", err))
+		return shared, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[1].(string))
+		habit := Habit{
+			HabitId:     habitId,
+			HabitCycle:  node.Values[4].(string),
+			Created:     node.Values[5].(time.Time),
+			ExcusedDays: parseExcusedDays(node.Values[9]),
+		}
+		if lastCompleted := node.Values[6]; lastCompleted != nil {
+			completed := lastCompleted.(time.Time)
+			habit.LastCompleted = &completed
+		}
+		shared = append(shared, SharedHabit{
+			Owner:            node.Values[0].(string),
+			HabitId:          habitId,
+			HabitName:        node.Values[2].(string),
+			HabitDescription: node.Values[3].(string),
+			HabitCycle:       habit.HabitCycle,
+			LastCompleted:    habit.LastCompleted,
+			Status:           getHabitStatus(habit),
+			Streak:           node.Values[7].(int64),
+			LongestStreak:    node.Values[8].(int64),
+		})
+	}
+	return shared, nil
+}
+
+type Challenge struct {
+	ChallengeId          uuid.UUID `json:"challenge_id"`
+	ChallengeName        string    `json:"challenge_name" binding:"required"`
+	ChallengeDescription string    `json:"challenge_description" binding:"required"`
+	HabitCycle           string    `json:"habit_cycle" binding:"required"`
+	HabitTarget          float64   `json:"habit_target"`
+	HabitUnit            string    `json:"habit_unit"`
+	DurationDays         int64     `json:"duration_days" binding:"required"`
+	StartDate            string    `json:"start_date"`
+	EndDate              string    `json:"end_date"`
+	CreatedBy            string    `json:"created_by"`
+	Participants         int64     `json:"participants"`
+}
+
+type ChallengeParticipant struct {
+	Uid             string
+	HabitId         uuid.UUID
+	Streak          int64
+	LongestStreak   int64
+	CompletionDates []string
+	ExcusedDays     []string
+}
+
+// function used to generate the config used to create a participant
+// habit from a challenge definition. habits are created at the start
+// of the challenge so that they are not due before it begins
+func challengeHabitConfig(user string, challenge Challenge) map[string]interface{} {
+	created := clock.Now()
+	if start, err := time.Parse(excusalDateFormat, challenge.StartDate); err == nil && start.After(created) {
+		created = start
+	}
+	return map[string]interface{}{
+		"uid":               user,
+		"challenge_id":      challenge.ChallengeId.String(),
+		"habit_id":          uuid.New().String(),
+		"habit_name":        challenge.ChallengeName,
+		"habit_description": challenge.ChallengeDescription,
+		"habit_cycle":       orderCyclesString(challenge.HabitCycle),
+		"habit_target":      challenge.HabitTarget,
+		"habit_unit":        challenge.HabitUnit,
+		"created":           created,
+	}
+}
+
+// define query used to create a participant habit linked to a challenge
+const createChallengeHabitQuery = `MATCH (u:User {uid: $uid}), (c:Challenge {challenge_id: $challenge_id})
+        CREATE (u)-[:OWNS]->(h:Habit {
+            habit_name: $habit_name,
+            habit_id: $habit_id,
+            habit_description: $habit_description,
+            habit_cycle: $habit_cycle,
+            habit_target: $habit_target,
+            habit_unit: $habit_unit,
+            last_completed: null,
+            created: $created,
+            streak: 0,
+            longest_streak: 0,
+            freezes: 0
+        })-[:PART_OF]->(c)`
+
+// function used to create a new challenge. the creating user
+// automatically joins the challenge with a new habit
+func (db *GraphPersistence) CreateChallenge(user string, challenge Challenge) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating new challenge %+v for user %s...", challenge, user))
+	challenge.ChallengeId = uuid.New()
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":                   user,
+		"challenge_id":          challenge.ChallengeId.String(),
+		"challenge_name":        challenge.ChallengeName,
+		"challenge_description": challenge.ChallengeDescription,
+		"habit_cycle":           orderCyclesString(challenge.HabitCycle),
+		"habit_target":          challenge.HabitTarget,
+		"habit_unit":            challenge.HabitUnit,
+		"duration_days":         challenge.DurationDays,
+		"start_date":            challenge.StartDate,
+		"end_date":              challenge.EndDate,
+		"created":               clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        CREATE (u)-[:CREATED]->(:Challenge {
+            challenge_id: $challenge_id,
+            challenge_name: $challenge_name,
+            challenge_description: $challenge_description,
+            habit_cycle: $habit_cycle,
+            habit_target: $habit_target,
+            habit_unit: $habit_unit,
+            duration_days: $duration_days,
+            start_date: $start_date,
+            end_date: $end_date,
+            created: $created
+        })`
+		if _, err := tx.Run(query, cfg); err != nil {
+			log.Error(fmt.Errorf("unable to create challenge: %+v", err))
+			return nil, err
+		}
+		return tx.Run(createChallengeHabitQuery, challengeHabitConfig(user, challenge))
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create challenge: %+v", err))
+		return uuid.Nil, err
+	}
+	return challenge.ChallengeId, nil
+}
+
+// function used to retrieve a challenge by challenge ID
+func (db *GraphPersistence) GetChallenge(challengeId uuid.UUID) (Challenge, error) {
+	log.Debug(fmt.Sprintf("retrieving challenge %s...", challengeId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"challenge_id": challengeId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (o:User)-[:CREATED]->(c:Challenge {challenge_id: $challenge_id})
+        RETURN c.challenge_name, c.challenge_description, c.habit_cycle, c.habit_target,
+        c.habit_unit, c.duration_days, c.start_date, c.end_date, o.uid,
+        size([(c)<-[:PART_OF]-(h:Habit) WHERE h.deleted_at IS NULL | h])`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrChallengeDoesNotExist
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve challenge: %+v", err))
+		return Challenge{}, err
+	}
+	return Challenge{
+		ChallengeId:          challengeId,
+		ChallengeName:        node.Values[0].(string),
+		ChallengeDescription: node.Values[1].(string),
+		HabitCycle:           node.Values[2].(string),
+		HabitTarget:          node.Values[3].(float64),
+		HabitUnit:            node.Values[4].(string),
+		DurationDays:         node.Values[5].(int64),
+		StartDate:            node.Values[6].(string),
+		EndDate:              node.Values[7].(string),
+		CreatedBy:            node.Values[8].(string),
+		Participants:         node.Values[9].(int64),
+	}, nil
+}
+
+// function used to retrieve all challenges a user participates in
+func (db *GraphPersistence) GetUserChallenges(user string) ([]Challenge, error) {
+	log.Debug(fmt.Sprintf("retrieving challenges for user %s...", user))
+	challenges := []Challenge{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(:Habit)-[:PART_OF]->(c:Challenge)<-[:CREATED]-(o:User)
+        RETURN DISTINCT c.challenge_id, c.challenge_name, c.challenge_description, c.habit_cycle,
+        c.habit_target, c.habit_unit, c.duration_days, c.start_date, c.end_date, o.uid,
+        size([(c)<-[:PART_OF]-(h:Habit) WHERE h.deleted_at IS NULL | h])
+        ORDER BY c.start_date DESC`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve user challenges: %+v", err))
+		return challenges, err
+	}
+	for _, node := range nodes {
+		challengeId, _ := uuid.Parse(node.Values[0].(string))
+		challenges = append(challenges, Challenge{
+			ChallengeId:          challengeId,
+			ChallengeName:        node.Values[1].(string),
+			ChallengeDescription: node.Values[2].(string),
+			HabitCycle:           node.Values[3].(string),
+			HabitTarget:          node.Values[4].(float64),
+			HabitUnit:            node.Values[5].(string),
+			DurationDays:         node.Values[6].(int64),
+			StartDate:            node.Values[7].(string),
+			EndDate:              node.Values[8].(string),
+			CreatedBy:            node.Values[9].(string),
+			Participants:         node.Values[10].(int64),
+		})
+	}
+	return challenges, nil
+}
+
+// function used to join a challenge. a new habit is created for the
+// user from the challenge definition and linked to the challenge
+func (db *GraphPersistence) JoinChallenge(user string, challenge Challenge) error {
+	log.Debug(fmt.Sprintf("user %s joining challenge %s...", user, challenge.ChallengeId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":          user,
+		"challenge_id": challenge.ChallengeId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)-[:PART_OF]->(:Challenge {challenge_id: $challenge_id})
+        RETURN COUNT(h)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) > 0 {
+			return nil, ErrAlreadyParticipant
+		}
+		return tx.Run(createChallengeHabitQuery, challengeHabitConfig(user, challenge))
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to join challenge: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to leave a challenge. the habit of the user is
+// unlinked from the challenge but kept as a personal habit
+func (db *GraphPersistence) LeaveChallenge(user string, challengeId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("user %s leaving challenge %s...", user, challengeId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":          user,
+		"challenge_id": challengeId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(:Habit)-[r:PART_OF]->(:Challenge {challenge_id: $challenge_id})
+        DELETE r
+        RETURN COUNT(r)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrNotParticipant
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to leave challenge: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all participants of a challenge along with
+// the days on which each participant completed their habit during the
+// challenge. only participants of a challenge can view other participants
+func (db *GraphPersistence) GetChallengeParticipants(user string, challenge Challenge) ([]ChallengeParticipant, error) {
+	log.Debug(fmt.Sprintf("retrieving participants for challenge %s...", challenge.ChallengeId))
+	participants := []ChallengeParticipant{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":          user,
+		"challenge_id": challenge.ChallengeId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(:Habit)-[:PART_OF]->(c:Challenge {challenge_id: $challenge_id})
+        MATCH (c)<-[:PART_OF]-(h:Habit)<-[:OWNS]-(p:User)
+        WHERE h.deleted_at IS NULL
+        RETURN p.uid, h.habit_id, h.streak, coalesce(h.longest_streak, h.streak),
+        [(h)-[:OWNS]->(hc:HabitCompletion)
+            WHERE toString(date(hc.event_timestamp)) >= c.start_date
+            AND toString(date(hc.event_timestamp)) < c.end_date
+            | toString(date(hc.event_timestamp))],
+        [(h)-[:OWNS]->(e:HabitExcusal) | e.excusal_date]`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve challenge participants: %+v", err))
+		return participants, err
+	}
+	if len(nodes) == 0 {
+		return participants, ErrNotParticipant
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[1].(string))
+		participants = append(participants, ChallengeParticipant{
+			Uid:             node.Values[0].(string),
+			HabitId:         habitId,
+			Streak:          node.Values[2].(int64),
+			LongestStreak:   node.Values[3].(int64),
+			CompletionDates: parseExcusedDays(node.Values[4]),
+			ExcusedDays:     parseExcusedDays(node.Values[5]),
+		})
+	}
+	return participants, nil
+}
+
+type HabitTemplate struct {
+	TemplateId       uuid.UUID `json:"template_id"`
+	HabitName        string    `json:"habit_name" binding:"required"`
+	HabitDescription string    `json:"habit_description" binding:"required"`
+	HabitCycle       string    `json:"habit_cycle" binding:"required"`
+	HabitTarget      float64   `json:"habit_target"`
+	HabitUnit        string    `json:"habit_unit"`
+	System           bool      `json:"system"`
+	PublishedBy      *string   `json:"published_by"`
+	Created          time.Time `json:"created"`
+}
+
+type HabitTemplateBundle struct {
+	BundleId          uuid.UUID       `json:"bundle_id"`
+	BundleName        string          `json:"bundle_name" binding:"required"`
+	BundleDescription string          `json:"bundle_description" binding:"required"`
+	TemplateIds       []uuid.UUID     `json:"template_ids" binding:"required"`
+	Templates         []HabitTemplate `json:"templates"`
+}
+
+// function used to convert a template node returned as
+// a map from the graph into a habit template
+func parseHabitTemplate(value interface{}) HabitTemplate {
+	props := value.(map[string]interface{})
+	templateId, _ := uuid.Parse(props["template_id"].(string))
+	template := HabitTemplate{
+		TemplateId:       templateId,
+		HabitName:        props["habit_name"].(string),
+		HabitDescription: props["habit_description"].(string),
+		HabitCycle:       props["habit_cycle"].(string),
+		HabitTarget:      props["habit_target"].(float64),
+		HabitUnit:        props["habit_unit"].(string),
+		System:           props["system"].(bool),
+		Created:          props["created"].(time.Time),
+	}
+	if publisher, ok := props["published_by"].(string); ok {
+		template.PublishedBy = &publisher
+	}
+	return template
+}
+
+// define projection used to return template nodes as maps
+const habitTemplateProjection = `{
+            template_id: t.template_id, habit_name: t.habit_name,
+            habit_description: t.habit_description, habit_cycle: t.habit_cycle,
+            habit_target: t.habit_target, habit_unit: t.habit_unit,
+            system: t.system, created: t.created, published_by: p.uid
+        }`
+
+// function used to retrieve the template catalogue. the catalogue
+// contains all system templates and all templates published by users
+func (db *GraphPersistence) GetHabitTemplates() ([]HabitTemplate, error) {
+	log.Debug("retrieving habit template catalogue...")
+	templates := []HabitTemplate{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (t:HabitTemplate)
+        OPTIONAL MATCH (p:User)-[:PUBLISHED]->(t)
+        RETURN t` + habitTemplateProjection + `
+        ORDER BY t.system DESC, t.habit_name`
+		return neo4j.Collect(tx.Run(query, nil))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit templates: %+v", err))
+		return templates, err
+	}
+	for _, node := range nodes {
+		templates = append(templates, parseHabitTemplate(node.Values[0]))
+	}
+	return templates, nil
+}
+
+// function used to add a new template to the catalogue. system
+// templates are curated by admins, all other templates are linked
+// to the user that published them
+func (db *GraphPersistence) CreateHabitTemplate(user string, template HabitTemplate,
+	system bool) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating habit template %+v for user %s...", template, user))
+	templateId := uuid.New()
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":               user,
+		"template_id":       templateId.String(),
+		"habit_name":        template.HabitName,
+		"habit_description": template.HabitDescription,
+		"habit_cycle":       orderCyclesString(template.HabitCycle),
+		"habit_target":      template.HabitTarget,
+		"habit_unit":        template.HabitUnit,
+		"system":            system,
+		"created":           clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `CREATE (t:HabitTemplate {
+            template_id: $template_id,
+            habit_name: $habit_name,
+            habit_description: $habit_description,
+            habit_cycle: $habit_cycle,
+            habit_target: $habit_target,
+            habit_unit: $habit_unit,
+            system: $system,
+            created: $created
+        })`
+		if !system {
+			query += `
+        WITH t
+        MATCH (u:User {uid: $uid})
+        CREATE (u)-[:PUBLISHED]->(t)`
+		}
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create habit template: %+v", err))
+		return uuid.Nil, err
+	}
+	return templateId, nil
+}
+
+// function used to delete a template from the catalogue. users can
+// only delete templates they published, admins can delete any template
+func (db *GraphPersistence) DeleteHabitTemplate(user string, templateId uuid.UUID, admin bool) error {
+	log.Debug(fmt.Sprintf("deleting habit template %s...", templateId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":         user,
+		"template_id": templateId.String(),
+		"admin":       admin,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (t:HabitTemplate {template_id: $template_id})
+        WHERE $admin OR (:User {uid: $uid})-[:PUBLISHED]->(t)
+        DETACH DELETE t
+        RETURN COUNT(t)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrTemplateDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete habit template: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve all template bundles in the catalogue
+func (db *GraphPersistence) GetHabitTemplateBundles() ([]HabitTemplateBundle, error) {
+	log.Debug("retrieving habit template bundles...")
+	bundles := []HabitTemplateBundle{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (b:HabitTemplateBundle)
+        OPTIONAL MATCH (b)-[:CONTAINS]->(t:HabitTemplate)
+        OPTIONAL MATCH (p:User)-[:PUBLISHED]->(t)
+        WITH b, t, p ORDER BY t.habit_name
+        RETURN b.bundle_id, b.bundle_name, b.bundle_description,
+        COLLECT(CASE WHEN t IS NULL THEN NULL ELSE t` + habitTemplateProjection + ` END)
+        ORDER BY b.bundle_name`
+		return neo4j.Collect(tx.Run(query, nil))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit template bundles: %+v", err))
+		return bundles, err
+	}
+	for _, node := range nodes {
+		bundleId, _ := uuid.Parse(node.Values[0].(string))
+		bundle := HabitTemplateBundle{
+			BundleId:          bundleId,
+			BundleName:        node.Values[1].(string),
+			BundleDescription: node.Values[2].(string),
+			TemplateIds:       []uuid.UUID{},
+			Templates:         []HabitTemplate{},
+		}
+		for _, value := range node.Values[3].([]interface{}) {
+			template := parseHabitTemplate(value)
+			bundle.TemplateIds = append(bundle.TemplateIds, template.TemplateId)
+			bundle.Templates = append(bundle.Templates, template)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// function used to create a new template bundle. all templates
+// in the bundle must exist in the catalogue
+func (db *GraphPersistence) CreateHabitTemplateBundle(bundle HabitTemplateBundle) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating habit template bundle %+v...", bundle))
+	bundleId := uuid.New()
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	templateIds := []string{}
+	for _, templateId := range bundle.TemplateIds {
+		templateIds = append(templateIds, templateId.String())
+	}
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"bundle_id":          bundleId.String(),
+		"bundle_name":        bundle.BundleName,
+		"bundle_description": bundle.BundleDescription,
+		"template_ids":       templateIds,
+		"created":            clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `CREATE (b:HabitTemplateBundle {
+            bundle_id: $bundle_id,
+            bundle_name: $bundle_name,
+            bundle_description: $bundle_description,
+            created: $created
+        })
+        WITH b
+        MATCH (t:HabitTemplate) WHERE t.template_id IN $template_ids
+        CREATE (b)-[:CONTAINS]->(t)
+        RETURN COUNT(DISTINCT t)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		// rollback transaction if any templates are missing
+		if node.Values[0].(int64) != int64(len(templateIds)) {
+			return nil, ErrTemplateDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create habit template bundle: %+v", err))
+		return uuid.Nil, err
+	}
+	return bundleId, nil
+}
+
+// function used to delete a template bundle. templates in
+// the bundle are kept in the catalogue
+func (db *GraphPersistence) DeleteHabitTemplateBundle(bundleId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting habit template bundle %s...", bundleId))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"bundle_id": bundleId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (b:HabitTemplateBundle {bundle_id: $bundle_id})
+        DETACH DELETE b
+        RETURN COUNT(b)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrBundleDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete habit template bundle: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// define query used to create habits for a user from a set of
+// templates. the templates are expected to be matched as t
+const instantiateTemplatesQuery = `
+        MATCH (u:User {uid: $uid})
+        CREATE (u)-[:OWNS]->(h:Habit {
+            habit_name: t.habit_name,
+            habit_id: randomUUID(),
+            habit_description: t.habit_description,
+            habit_cycle: t.habit_cycle,
+            habit_target: t.habit_target,
+            habit_unit: t.habit_unit,
+            last_completed: null,
+            created: $created,
+            streak: 0,
+            longest_streak: 0,
+            freezes: 0
+        })-[:INSTANCE_OF]->(t)
+        RETURN COUNT(h)`
+
+// function used to create habits for a user from a list of templates.
+// all habits are created in a single transaction, which is rolled back
+// if any of the templates cannot be found
+func (db *GraphPersistence) InstantiateHabitTemplates(user string, templateIds []uuid.UUID) (int64, error) {
+	log.Debug(fmt.Sprintf("instantiating templates %+v for user %s...", templateIds, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	ids := []string{}
+	for _, templateId := range templateIds {
+		if !utils.StringSliceContains(ids, templateId.String()) {
+			ids = append(ids, templateId.String())
+		}
+	}
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":          user,
+		"template_ids": ids,
+		"created":      clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (t:HabitTemplate) WHERE t.template_id IN $template_ids` +
+			instantiateTemplatesQuery
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) != int64(len(ids)) {
+			return nil, ErrTemplateDoesNotExist
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to instantiate habit templates: %+v", err))
+		return 0, err
+	}
+	return node.Values[0].(int64), nil
+}
+
+// function used to create habits for a user from all templates
+// contained in a bundle within a single transaction
+func (db *GraphPersistence) InstantiateHabitTemplateBundle(user string, bundleId uuid.UUID) (int64, error) {
+	log.Debug(fmt.Sprintf("instantiating template bundle %s for user %s...", bundleId, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid":       user,
+		"bundle_id": bundleId.String(),
+		"created":   clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:HabitTemplateBundle {bundle_id: $bundle_id})-[:CONTAINS]->(t:HabitTemplate)` +
+			instantiateTemplatesQuery
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrBundleDoesNotExist
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to instantiate habit template bundle: %+v", err))
+		return 0, err
+	}
+	return node.Values[0].(int64), nil
+}
+
+type HabitCoCompletion struct {
+	HabitId         uuid.UUID
+	OtherHabitId    uuid.UUID
+	CoCompletedDays int64
+}
+
+// function used to retrieve all active habits for a user along
+// with their full completion history
+func (db *GraphPersistence) GetUserHabitHistory(user string) ([]HabitHistory, error) {
+	log.Debug(fmt.Sprintf("retrieving habit history for user %s...", user))
+	history := []HabitHistory{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)
+        WHERE h.deleted_at IS NULL AND NOT coalesce(h.archived, false)
+        RETURN h.habit_id, h.habit_name, h.habit_cycle, h.created,
+        [(h)-[:OWNS]->(c:HabitCompletion) | c.event_timestamp],
+        [(h)-[:OWNS]->(e:HabitExcusal) | e.excusal_date]
+        ORDER BY h.habit_name`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit history: %+v", err))
+		return history, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[0].(string))
+		completions := []time.Time{}
+		for _, ts := range node.Values[4].([]interface{}) {
+			completions = append(completions, ts.(time.Time))
+		}
+		history = append(history, HabitHistory{
+			Habit: Habit{
+				HabitId:     habitId,
+				HabitName:   node.Values[1].(string),
+				HabitCycle:  node.Values[2].(string),
+				Created:     node.Values[3].(time.Time),
+				ExcusedDays: parseExcusedDays(node.Values[5]),
+			},
+			Completions: completions,
+		})
+	}
+	return history, nil
+}
+
+// function used to retrieve the number of days on which each pair
+// of habits owned by a user were both completed. completions are
+// grouped by day first so that only habits completed on the same
+// day are paired
+func (db *GraphPersistence) GetHabitCoCompletions(user string) ([]HabitCoCompletion, error) {
+	log.Debug(fmt.Sprintf("retrieving habit co-completions for user %s...", user))
+	pairs := []HabitCoCompletion{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+	// generate config metadata for query
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(h:Habit)-[:OWNS]->(c:HabitCompletion)
+        WHERE h.deleted_at IS NULL
+        WITH date(c.event_timestamp) AS day, COLLECT(DISTINCT h.habit_id) AS habits
+        WHERE size(habits) > 1
+        UNWIND habits AS h1
+        UNWIND habits AS h2
+        WITH day, h1, h2 WHERE h1 < h2
+        RETURN h1, h2, COUNT(day)`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve habit co-completions: %+v", err))
+		return pairs, err
+	}
+	for _, node := range nodes {
+		habitId, _ := uuid.Parse(node.Values[0].(string))
+		otherHabitId, _ := uuid.Parse(node.Values[1].(string))
+		pairs = append(pairs, HabitCoCompletion{
+			HabitId:         habitId,
+			OtherHabitId:    otherHabitId,
+			CoCompletedDays: node.Values[2].(int64),
+		})
+	}
+	return pairs, nil
+}
+
+type Goal struct {
+	GoalId          uuid.UUID   `json:"goal_id"`
+	GoalName        string      `json:"goal_name" binding:"required"`
+	GoalDescription string      `json:"goal_description"`
+	TargetDate      *string     `json:"target_date"`
+	Created         time.Time   `json:"created"`
+	Habits          []GoalHabit `json:"habits"`
+	Items           []GoalItem  `json:"items"`
+	Progress        float64     `json:"progress"`
+	HabitProgress   float64     `json:"habit_progress"`
+	ItemProgress    float64     `json:"item_progress"`
+}
+
+type GoalHabit struct {
+	HabitId        uuid.UUID `json:"habit_id"`
+	HabitName      string    `json:"habit_name"`
+	HabitCycle     string    `json:"habit_cycle"`
+	Status         string    `json:"status"`
+	Streak         int64     `json:"streak"`
+	CompletionRate float64   `json:"completion_rate"`
+	habit          Habit
+	completions    []time.Time
+}
+
+type GoalItem struct {
+	ItemId    uuid.UUID  `json:"item_id"`
+	ItemTitle string     `json:"item_title"`
+	Completed bool       `json:"completed"`
+	DueAt     *time.Time `json:"due_at"`
+}
+
+// define projection used to return goals along with the habits
+// and TODO items linked to them (requires u and g)
+const goalProjection = `g.goal_id, g.goal_name, g.goal_description, g.target_date, g.created,
+        [(g)<-[:CONTRIBUTES_TO]-(h:Habit) WHERE h.deleted_at IS NULL | {
+            habit_id: h.habit_id,
+            habit_name: h.habit_name,
+            habit_cycle: h.habit_cycle,
+            created: h.created,
+            last_completed: h.last_completed,
+            streak: h.streak,
+            excused: [(h)-[:OWNS]->(e:HabitExcusal) | e.excusal_date],
+            completions: [(h)-[:OWNS]->(c:HabitCompletion) | c.event_timestamp]
+        }],
+        [(g)<-[:CONTRIBUTES_TO]-(t:TODO) | {
+            item_id: t.item_id,
+            item_title: t.item_title,
+            completed: t.completed,
+            due_at: t.due_at
+        }]`
+
+// function used to convert a set of values returned using
+// the goal projection into a goal
+func parseGoal(values []interface{}) Goal {
+	goalId, _ := uuid.Parse(values[0].(string))
+	goal := Goal{
+		GoalId:          goalId,
+		GoalName:        values[1].(string),
+		GoalDescription: values[2].(string),
+		Created:         values[4].(time.Time),
+		Habits:          []GoalHabit{},
+		Items:           []GoalItem{},
+	}
+	if values[3] != nil {
+		targetDate := values[3].(string)
+		goal.TargetDate = &targetDate
+	}
+
+	for _, value := range values[5].([]interface{}) {
+		h := value.(map[string]interface{})
+		habitId, _ := uuid.Parse(h["habit_id"].(string))
+		habit := Habit{
+			HabitId:     habitId,
+			HabitName:   h["habit_name"].(string),
+			HabitCycle:  h["habit_cycle"].(string),
+			Created:     h["created"].(time.Time),
+			Streak:      h["streak"].(int64),
+			ExcusedDays: parseExcusedDays(h["excused"]),
+		}
+		if h["last_completed"] != nil {
+			completed := h["last_completed"].(time.Time)
+			habit.LastCompleted = &completed
+		}
+		completions := []time.Time{}
+		for _, ts := range h["completions"].([]interface{}) {
+			completions = append(completions, ts.(time.Time))
+		}
+		goal.Habits = append(goal.Habits, GoalHabit{
+			HabitId:     habitId,
+			HabitName:   habit.HabitName,
+			HabitCycle:  habit.HabitCycle,
+			Streak:      habit.Streak,
+			habit:       habit,
+			completions: completions,
+		})
+	}
+
+	for _, value := range values[6].([]interface{}) {
+		t := value.(map[string]interface{})
+		itemId, _ := uuid.Parse(t["item_id"].(string))
+		item := GoalItem{
+			ItemId:    itemId,
+			ItemTitle: t["item_title"].(string),
+			Completed: t["completed"].(bool),
+		}
+		if t["due_at"] != nil {
+			dueAt := t["due_at"].(time.Time)
+			item.DueAt = &dueAt
+		}
+		goal.Items = append(goal.Items, item)
+	}
+	return goal
+}
+
+// function used to create a new goal for a given user
+func (db *GraphPersistence) CreateGoal(user string, goal Goal) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating new goal for user %s...", user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	goalId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":              user,
+		"goal_id":          goalId.String(),
+		"goal_name":        goal.GoalName,
+		"goal_description": goal.GoalDescription,
+		"target_date":      goal.TargetDate,
+		"created":          clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        CREATE (u)-[:OWNS]->(g:Goal {
+            goal_id: $goal_id,
+            goal_name: $goal_name,
+            goal_description: $goal_description,
+            target_date: $target_date,
+            created: $created
+        })`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create goal: %+v", err))
+		return goalId, err
+	}
+	return goalId, nil
+}
+
+// function used to retrieve all goals of a given user along with
+// the habits and TODO items linked to them. TODO items are only
+// returned while the user still has access to them
+func (db *GraphPersistence) GetUserGoals(user string) ([]Goal, error) {
+	log.Debug(fmt.Sprintf("retrieving goals for user %s...", user))
+	goals := []Goal{}
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid": user,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(g:Goal)
+        RETURN ` + goalProjection + `
+        ORDER BY g.created`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve goals: %+v", err))
+		return goals, err
+	}
+	for _, node := range nodes {
+		goals = append(goals, parseGoal(node.Values))
+	}
+	return goals, nil
+}
+
+// function used to retrieve a goal with given goal ID
+func (db *GraphPersistence) GetGoal(user string, goalId uuid.UUID) (Goal, error) {
+	log.Debug(fmt.Sprintf("retrieving goal %s for user %s...", goalId, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":     user,
+		"goal_id": goalId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(g:Goal {goal_id: $goal_id})
+        RETURN ` + goalProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrGoalDoesNotExist
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve goal: %+v", err))
+		return Goal{}, err
+	}
+	return parseGoal(node.Values), nil
+}
+
+// function used to update the name, description and
+// target date of a goal with given goal ID
+func (db *GraphPersistence) UpdateGoal(user string, goalId uuid.UUID, goal Goal) error {
+	log.Debug(fmt.Sprintf("updating goal %s for user %s...", goalId, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":              user,
+		"goal_id":          goalId.String(),
+		"goal_name":        goal.GoalName,
+		"goal_description": goal.GoalDescription,
+		"target_date":      goal.TargetDate,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(g:Goal {goal_id: $goal_id})
+        SET g.goal_name = $goal_name, g.goal_description = $goal_description,
+        g.target_date = $target_date
+        RETURN g.goal_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrGoalDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update goal: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to delete a goal with given goal ID. linked
+// habits and TODO items are left untouched
+func (db *GraphPersistence) DeleteGoal(user string, goalId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting goal %s for user %s...", goalId, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":     user,
+		"goal_id": goalId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(g:Goal {goal_id: $goal_id})
+        WITH g, g.goal_id AS goalId
+        DETACH DELETE g
+        RETURN goalId`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrGoalDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete goal: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to link (or unlink) a node matched by the given
+// pattern (binding the node to n and the user to u) to a goal.
+// the given error is returned if the node cannot be found
+func (db *GraphPersistence) setGoalLink(user string, goalId uuid.UUID, pattern string,
+	cfg map[string]interface{}, linked bool, notFound error) error {
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg["uid"] = user
+	cfg["goal_id"] = goalId.String()
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(g:Goal {goal_id: $goal_id})
+        RETURN g.goal_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrGoalDoesNotExist
+		}
+
+		query = `MATCH (g:Goal {goal_id: $goal_id})
+        ` + pattern
+		if linked {
+			query += `
+            MERGE (n)-[:CONTRIBUTES_TO]->(g)
+            RETURN count(n)`
+		} else {
+			query += `
+            OPTIONAL MATCH (n)-[r:CONTRIBUTES_TO]->(g)
+            DELETE r
+            RETURN count(n)`
+		}
+		results, err = tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, notFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update goal links: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to link (or unlink) a habit to a goal
+func (db *GraphPersistence) SetGoalHabit(user string, goalId, habitId uuid.UUID, linked bool) error {
+	log.Debug(fmt.Sprintf("updating link of habit %s to goal %s for user %s...", habitId, goalId, user))
+	pattern := `MATCH (:User {uid: $uid})-[:OWNS]->(n:Habit {habit_id: $habit_id})
+        WHERE n.deleted_at IS NULL`
+	cfg := map[string]interface{}{"habit_id": habitId.String()}
+	return db.setGoalLink(user, goalId, pattern, cfg, linked, ErrHabitDoesNotExist)
+}
+
+// function used to link (or unlink) a TODO item to a goal. note
+// that access to the item must be checked with the TODO API
+func (db *GraphPersistence) SetGoalTodoItem(user string, goalId, itemId uuid.UUID, linked bool) error {
+	log.Debug(fmt.Sprintf("updating link of TODO item %s to goal %s for user %s...", itemId, goalId, user))
+	pattern := `MATCH (n:TODO {item_id: $item_id})`
+	cfg := map[string]interface{}{"item_id": itemId.String()}
+	return db.setGoalLink(user, goalId, pattern, cfg, linked, ErrTodoItemDoesNotExist)
+}
+
+// function used to promote a TODO item into a recurring habit. the
+// habit is named after the item and linked to any goals of the user
+// that the item contributes to. note that the item must have been
+// marked as promoted with the TODO API
+func (db *GraphPersistence) PromoteTodoItem(user string, itemId uuid.UUID, habit Habit) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("promoting TODO item %s to habit for user %s...", itemId, user))
+	// create new persistence session for graph and defer closing
+	session := db.NewSession()
+	defer session.Close()
+
+	habitId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":               user,
+		"item_id":           itemId.String(),
+		"habit_id":          habitId.String(),
+		"habit_description": habit.HabitDescription,
+		"habit_cycle":       orderCyclesString(habit.HabitCycle),
+		"habit_target":      habit.HabitTarget,
+		"habit_unit":        habit.HabitUnit,
+		"created":           clock.Now(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid}), (t:TODO {item_id: $item_id})
+        CREATE (h:Habit {
+            habit_name: t.item_title,
+            habit_id: $habit_id,
+            habit_description: CASE WHEN $habit_description = '' THEN t.item_content ELSE $habit_description END,
+            habit_cycle: $habit_cycle,
+            habit_target: $habit_target,
+            habit_unit: $habit_unit,
+            last_completed: null,
+            created: $created,
+            streak: 0,
+            longest_streak: 0,
+            freezes: 0
+        })
+        CREATE (u)-[:OWNS]->(h), (h)-[:PROMOTED_FROM]->(t)
+        WITH u, h, t
+        OPTIONAL MATCH (t)-[:CONTRIBUTES_TO]->(g:Goal)<-[:OWNS]-(u)
+        FOREACH (x IN CASE WHEN g IS NULL THEN [] ELSE [g] END |
+            MERGE (h)-[:CONTRIBUTES_TO]->(x))
+        RETURN DISTINCT h.habit_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrTodoItemDoesNotExist
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to promote TODO item: %+v", err))
+		return habitId, err
+	}
+	return habitId, nil
+}
@@ -0,0 +1,137 @@
+package habits
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// define number of habits processed per transaction
+	reconcileBatchSize = 500
+)
+
+// function used to run the streak reconciliation job every night
+// at the given hour (UTC). the job blocks and should be run in
+// a separate goroutine
+func RunStreakReconciler(hour int) {
+	log.Info(fmt.Sprintf("starting streak reconciler at %02d:00 UTC", hour))
+	for {
+		now := clock.Now()
+		year, month, day := now.Date()
+		next := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+		if !next.After(now) {
+			next = next.Add(time.Hour * 24)
+		}
+		log.Debug(fmt.Sprintf("next streak reconciliation scheduled at %s", next))
+		time.Sleep(next.Sub(now))
+
+		if _, err := ReconcileHabitStreaks(); err != nil {
+			log.Error(fmt.Errorf("unable to reconcile habit streaks: %+v", err))
+		}
+	}
+}
+
+// function used to walk every habit in the graph and recompute its
+// status, streak and longest streak from completion history. only
+// habits whose values have changed are written back, and overdue
+// habits are left untouched while the user can still spend a streak
+// freeze to excuse the missed day. habits changed while the job runs
+// are skipped. the number of corrected habits is returned
+func ReconcileHabitStreaks() (int, error) {
+	log.Info("reconciling habit streaks...")
+	corrected := 0
+	now := clock.Now()
+	after := ""
+	for {
+		batch, err := persistence.GetHabitHistoryBatch(after, reconcileBatchSize)
+		if err != nil {
+			return corrected, err
+		}
+
+		reconciliations := []HabitReconciliation{}
+		for _, history := range batch {
+			reconciliation := reconcileHabit(history, now)
+			if reconciliation.Status == "overdue" && history.Habit.Freezes > 0 {
+				log.Debug(fmt.Sprintf("skipping reconciliation for habit %s: streak freezes available",
+					history.Habit.HabitId))
+				continue
+			}
+			if !reconciliationChanged(history.Habit, reconciliation) {
+				continue
+			}
+			log.Debug(fmt.Sprintf("correcting streak for habit %s from %d to %d",
+				history.Habit.HabitId, history.Habit.Streak, reconciliation.Streak))
+			reconciliations = append(reconciliations, reconciliation)
+		}
+		applied, err := persistence.ApplyHabitReconciliations(reconciliations)
+		if err != nil {
+			return corrected, err
+		}
+		corrected += applied
+		if len(batch) < reconcileBatchSize {
+			break
+		}
+		after = batch[len(batch)-1].Habit.HabitId.String()
+	}
+	log.Info(fmt.Sprintf("reconciled habit streaks: corrected %d habit(s)", corrected))
+	return corrected, nil
+}
+
+// function used to determine if a reconciliation changes
+// any of the values currently stored against a habit
+func reconciliationChanged(habit Habit, reconciliation HabitReconciliation) bool {
+	if reconciliation.Streak != habit.Streak || reconciliation.LongestStreak != habit.LongestStreak {
+		return true
+	}
+	if reconciliation.LastCompleted == nil || habit.LastCompleted == nil {
+		return reconciliation.LastCompleted != habit.LastCompleted
+	}
+	return !reconciliation.LastCompleted.Equal(*habit.LastCompleted)
+}
+
+// function used to recompute habit status and streaks by replaying
+// completion history against the habit cycle. each completion is
+// evaluated with the same rules used when completing habits, and
+// the current streak is reset if the habit is overdue at the
+// given reference time
+func reconcileHabit(history HabitHistory, now time.Time) HabitReconciliation {
+	habit := history.Habit
+	habit.LastCompleted = nil
+	habit.Streak = 0
+
+	completions := append([]time.Time{}, history.Completions...)
+	sort.Slice(completions, func(i, j int) bool {
+		return completions[i].Before(completions[j])
+	})
+
+	var longest int64
+	for _, ts := range completions {
+		switch getHabitStatusAt(habit, ts) {
+		case "due":
+			habit.Streak++
+		case "overdue":
+			habit.Streak = 0
+		}
+		if habit.Streak > longest {
+			longest = habit.Streak
+		}
+		completed := ts
+		habit.LastCompleted = &completed
+	}
+
+	status := getHabitStatusAt(habit, now)
+	if status == "overdue" {
+		habit.Streak = 0
+	}
+	return HabitReconciliation{
+		HabitId:       habit.HabitId,
+		Status:        status,
+		Streak:        habit.Streak,
+		LongestStreak: longest,
+		LastCompleted: habit.LastCompleted,
+		Previous:      history.Habit,
+	}
+}
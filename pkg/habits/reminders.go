@@ -0,0 +1,162 @@
+package habits
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+var (
+	// define custom errors
+	ErrInvalidReminderSettings = errors.New("Invalid reminder settings")
+)
+
+const (
+	// define format used for reminder times, the window after a
+	// reminder time in which a reminder is still delivered and
+	// the period for which delivery records are kept
+	reminderTimeFormat = "15:04"
+	reminderWindow     = time.Hour
+	reminderRetention  = time.Hour * 24 * 7
+)
+
+// function used to validate reminder settings. reminder
+// times must be given as HH:MM
+func validateReminderSettings(settings ReminderSettings) error {
+	for _, t := range settings.ReminderTimes {
+		if _, err := time.Parse(reminderTimeFormat, t); err != nil {
+			log.Warn(fmt.Sprintf("received invalid reminder time %s", t))
+			return ErrInvalidReminderSettings
+		}
+	}
+	return nil
+}
+
+// function used to periodically evaluate habit reminders for all
+// users. reminders are delivered using the given notifier
+func RunReminderScheduler(interval time.Duration, notifier utils.Notifier) {
+	log.Info(fmt.Sprintf("starting habit reminder scheduler with interval %s", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		evaluateReminders(notifier)
+		<-ticker.C
+	}
+}
+
+// function used to evaluate and deliver reminders for all users
+// that have reminders enabled
+func evaluateReminders(notifier utils.Notifier) {
+	settings, err := persistence.GetEnabledReminderSettings()
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve reminder settings: %+v", err))
+		return
+	}
+	now := clock.Now()
+	for _, userSettings := range settings {
+		if err := evaluateUserReminders(userSettings, now, notifier); err != nil {
+			log.Error(fmt.Errorf("unable to evaluate reminders for user %s: %+v",
+				userSettings.Uid, err))
+		}
+	}
+	// remove old delivery records
+	if err := persistence.PurgeDeliveredReminders(now.Add(-reminderRetention)); err != nil {
+		log.Error(fmt.Errorf("unable to purge delivered reminders: %+v", err))
+	}
+}
+
+// function used to convert a reference time into the wall clock time
+// of a given location. habit due dates are stored as UTC days, so the
+// local wall clock time is used to evaluate habits in the users day
+func getLocalHabitTime(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	year, month, day := local.Date()
+	return time.Date(year, month, day, local.Hour(), local.Minute(),
+		local.Second(), local.Nanosecond(), time.UTC)
+}
+
+// function used to determine which of the reminder times configured
+// by a user are currently active in the users local time
+func getActiveReminderTimes(settings ReminderSettings, local time.Time) []string {
+	active := []string{}
+	year, month, day := local.Date()
+	for _, t := range settings.ReminderTimes {
+		parsed, err := time.Parse(reminderTimeFormat, t)
+		if err != nil {
+			continue
+		}
+		slot := time.Date(year, month, day, parsed.Hour(), parsed.Minute(), 0, 0, local.Location())
+		if !local.Before(slot) && local.Before(slot.Add(reminderWindow)) {
+			active = append(active, t)
+		}
+	}
+	return active
+}
+
+// function used to evaluate reminders for a single user. reminders
+// are sent for all due and overdue habits at each active reminder
+// time, and recorded so that they are not delivered twice
+func evaluateUserReminders(settings ReminderSettings, now time.Time,
+	notifier utils.Notifier) error {
+	loc, err := userLocation(settings.Uid)
+	if err != nil {
+		return err
+	}
+	local := now.In(loc)
+	active := getActiveReminderTimes(settings, local)
+	if len(active) == 0 {
+		return nil
+	}
+
+	date := local.Format(excusalDateFormat)
+	delivered, err := persistence.GetDeliveredReminderKeys(settings.Uid, date)
+	if err != nil {
+		return err
+	}
+	habits, err := persistence.GetUserHabits(settings.Uid, false)
+	if err != nil {
+		return err
+	}
+
+	// evaluate habit status in the local time of the user so that
+	// reminders are not sent for the previous or next day
+	habitTime := getLocalHabitTime(now, loc)
+	for _, reminderTime := range active {
+		for _, habit := range habits {
+			status := getHabitStatusAt(habit, habitTime)
+			if status != "due" && status != "overdue" {
+				continue
+			}
+			key := fmt.Sprintf("%s:%s:%s", habit.HabitId, date, reminderTime)
+			if utils.StringSliceContains(delivered, key) {
+				continue
+			}
+
+			notification := utils.Notification{
+				Uid:     settings.Uid,
+				Source:  "habits",
+				Subject: fmt.Sprintf("Habit %s is %s", habit.HabitName, status),
+				Message: habit.HabitDescription,
+				Data: map[string]interface{}{
+					"habit_id":      habit.HabitId,
+					"status":        status,
+					"streak":        habit.Streak,
+					"reminder_time": reminderTime,
+				},
+				Created: now,
+			}
+			if err := notifier.Notify(notification); err != nil {
+				log.Error(fmt.Errorf("unable to deliver reminder %s: %+v", key, err))
+				continue
+			}
+			if err := persistence.RecordReminderDelivery(settings.Uid, key, date); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package habits
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// function used to periodically purge habits that have been in
+// the trash for longer than the given retention period. habits are
+// purged along with all completion, excusal and progress nodes
+func RunTrashPurger(interval, retention time.Duration) {
+	log.Info(fmt.Sprintf("starting habit trash purger with interval %s and retention %s",
+		interval, retention))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purgeTrashedHabits(retention)
+		<-ticker.C
+	}
+}
+
+// function used to purge all habits trashed before the retention cutoff
+func purgeTrashedHabits(retention time.Duration) {
+	cutoff := clock.Now().Add(-retention)
+	purged, err := persistence.PurgeTrashedHabits(cutoff)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to purge trashed habits: %+v", err))
+		return
+	}
+	log.Info(fmt.Sprintf("purged %d habit(s) trashed before %s", purged, cutoff))
+}
@@ -1,26 +1,28 @@
 package habits
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
 )
 
 var (
-	validCycles = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+	// define custom errors
+	ErrInvalidDateRange = errors.New("Invalid date range")
+	ErrPastExcusalDate  = errors.New("Excusal date is in the past")
 )
 
-// helper function used to determine is a string
-// slice contains a given element
-func stringSliceContains(slice []string, element string) bool {
-	for _, ele := range slice {
-		if ele == element {
-			return true
-		}
-	}
-	return false
-}
+const (
+	// define format used to store excused and progress days
+	// and the maximum number of days that can be excused at once
+	excusalDateFormat = "2006-01-02"
+	maxExcusalDays    = 90
+)
 
 // function used to cast a slice of strings to
 // lowercase
@@ -38,7 +40,7 @@ func isValidCycle(cycle string) bool {
 	// iterate over days given in comma-separarted cycle
 	// and check that all days are valid
 	for _, day := range strings.Split(cycle, ",") {
-		if !stringSliceContains(validCycles, strings.ToLower(day)) {
+		if !utils.StringSliceContains(utils.ValidCycles, strings.ToLower(day)) {
 			log.Warn(fmt.Sprintf("cannot process cycle: invalid cycle day %s", day))
 			return false
 		}
@@ -54,8 +56,8 @@ func orderCyclesSlice(cycle string) []string {
 	cycleSlice := stringSliceToLower(strings.Split(cycle, ","))
 	// iterate over valid days (already ordered) and append
 	// present values into ordered array
-	for _, day := range validCycles {
-		if stringSliceContains(cycleSlice, day) {
+	for _, day := range utils.ValidCycles {
+		if utils.StringSliceContains(cycleSlice, day) {
 			ordered = append(ordered, day)
 		}
 	}
@@ -68,3 +70,71 @@ func orderCyclesString(cycle string) string {
 	ordered := orderCyclesSlice(cycle)
 	return strings.Join(ordered, ",")
 }
+
+// function used to check a list of dates and convert them
+// into the format used to store excused days. dates before the
+// given day (in the format used for excused days) are rejected
+// so that missed days cannot be excused after the fact
+func parseExcusalDates(dates []string, today string) ([]string, error) {
+	parsed := []string{}
+	if len(dates) == 0 || len(dates) > maxExcusalDays {
+		return parsed, ErrInvalidDateRange
+	}
+	for _, date := range dates {
+		ts, err := time.Parse(excusalDateFormat, date)
+		if err != nil {
+			log.Warn(fmt.Sprintf("cannot process excusal: invalid date %s", date))
+			return parsed, ErrInvalidDateRange
+		}
+		if ts.Format(excusalDateFormat) < today {
+			log.Warn(fmt.Sprintf("cannot process excusal: date %s is in the past", date))
+			return parsed, ErrPastExcusalDate
+		}
+		parsed = append(parsed, ts.Format(excusalDateFormat))
+	}
+	return parsed, nil
+}
+
+// function used to expand a date range into a list of
+// dates. both start and end dates are inclusive and
+// ranges starting before the given day are rejected
+func expandDateRange(start, end, today string) ([]string, error) {
+	dates := []string{}
+	startTs, err := time.Parse(excusalDateFormat, start)
+	if err != nil {
+		return dates, ErrInvalidDateRange
+	}
+	if startTs.Format(excusalDateFormat) < today {
+		return dates, ErrPastExcusalDate
+	}
+	endTs, err := time.Parse(excusalDateFormat, end)
+	if err != nil || endTs.Before(startTs) {
+		return dates, ErrInvalidDateRange
+	}
+	for ts := startTs; !ts.After(endTs); ts = ts.Add(time.Hour * 24) {
+		dates = append(dates, ts.Format(excusalDateFormat))
+	}
+	if len(dates) > maxExcusalDays {
+		return []string{}, ErrInvalidDateRange
+	}
+	return dates, nil
+}
+
+// define function used to look up the location of a user. the
+// lookup is replaced in tests to avoid accessing the graph
+var userLocation = getUserLocation
+
+// function used to retrieve the location of a user. users
+// with an invalid timezone are evaluated in UTC
+func getUserLocation(uid string) (*time.Location, error) {
+	timezone, err := persistence.GetUserTimezone(uid)
+	if err != nil {
+		return time.UTC, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Warn(fmt.Sprintf("invalid timezone %s for user %s: defaulting to UTC", timezone, uid))
+		return time.UTC, nil
+	}
+	return loc, nil
+}
@@ -2,7 +2,13 @@ package todo
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,14 +22,82 @@ var persistence *GraphPersistence
 // function used to generate new TODO api
 func NewTodoAPI() *gin.Engine {
 	router := gin.Default()
+	// health checks are registered before the user injection
+	// middleware so that container health probes need no user
+	router.GET("/todo/health_check", healthCheckHandler)
 	// add middleware to inject user ID into request context
 	router.Use(utils.UserInjectionMiddleware())
 
-	router.GET("/TODO/health_check", healthCheckHandler)
-	router.GET("/TODO/items", getTodoItemsHandler)
-	router.PATCH("/TODO/item/:itemId", completeTodoItemHandler)
-	router.POST("/TODO/new", newTodoItemHandler)
-	router.DELETE("/TODO/item/:itemId", deleteTodoItemHandler)
+	// redirect requests using the legacy uppercase prefix
+	router.Any("/TODO/*path", legacyPrefixHandler)
+	router.GET("/todo/items", getTodoItemsHandler)
+	router.GET("/todo/item/:itemId", getTodoItemHandler)
+	router.PUT("/todo/item/:itemId", updateTodoItemHandler)
+	router.PATCH("/todo/item/:itemId", completeTodoItemHandler)
+	router.PATCH("/todo/item/:itemId/fields", patchTodoItemHandler)
+	router.PATCH("/todo/complete/:itemId", completeTodoItemHandler)
+	router.PATCH("/todo/reopen/:itemId", reopenTodoItemHandler)
+	router.PATCH("/todo/item/:itemId/promote", promoteTodoItemHandler)
+	router.DELETE("/todo/item/:itemId/promote", releaseTodoItemPromotionHandler)
+	router.POST("/todo/new", newTodoItemHandler)
+	router.POST("/todo/quick", quickAddTodoItemHandler)
+	router.GET("/todo/next", getActionableTodoItemsHandler)
+	router.PUT("/todo/subtasks/:parentId/:itemId", addTodoSubtaskHandler)
+	router.DELETE("/todo/subtasks/:parentId/:itemId", removeTodoSubtaskHandler)
+	router.PUT("/todo/blocks/:blockerId/:itemId", addTodoBlockerHandler)
+	router.DELETE("/todo/blocks/:blockerId/:itemId", removeTodoBlockerHandler)
+
+	router.GET("/todo/projects", getProjectsHandler)
+	router.POST("/todo/projects", createProjectHandler)
+	router.PUT("/todo/projects/:projectId", updateProjectHandler)
+	router.DELETE("/todo/projects/:projectId", deleteProjectHandler)
+	router.PATCH("/todo/projects/:projectId/archive", archiveProjectHandler)
+	router.PATCH("/todo/projects/:projectId/unarchive", unarchiveProjectHandler)
+	router.PUT("/todo/projects/:projectId/order", reorderProjectItemsHandler)
+	router.PUT("/todo/projects/:projectId/items/:itemId", moveTodoItemHandler)
+	router.DELETE("/todo/projects/:projectId/items/:itemId", removeTodoItemFromProjectHandler)
+
+	router.GET("/todo/projects/:projectId/workflow", getProjectWorkflowHandler)
+	router.PUT("/todo/projects/:projectId/workflow", setProjectWorkflowHandler)
+	router.DELETE("/todo/projects/:projectId/workflow", deleteProjectWorkflowHandler)
+	router.GET("/todo/projects/:projectId/board", getProjectBoardHandler)
+	router.PATCH("/todo/item/:itemId/state", transitionTodoItemHandler)
+	router.GET("/todo/item/:itemId/history", getTodoItemHistoryHandler)
+
+	router.GET("/todo/projects/:projectId/collaborators", getProjectCollaboratorsHandler)
+	router.PUT("/todo/projects/:projectId/collaborators/:collaborator", shareProjectHandler)
+	router.DELETE("/todo/projects/:projectId/collaborators/:collaborator", removeProjectCollaboratorHandler)
+	router.PUT("/todo/assign/:itemId/:assignee", assignTodoItemHandler)
+	router.DELETE("/todo/assign/:itemId", unassignTodoItemHandler)
+
+	router.GET("/todo/timer", getRunningTimerHandler)
+	router.PATCH("/todo/timer/start/:itemId", startTimerHandler)
+	router.PATCH("/todo/timer/stop", stopTimerHandler)
+	router.GET("/todo/item/:itemId/time", getTodoItemTimeEntriesHandler)
+	router.POST("/todo/item/:itemId/time", logTimeEntryHandler)
+	router.DELETE("/todo/time/:entryId", deleteTimeEntryHandler)
+	router.GET("/todo/time/report", getTimeReportHandler)
+	router.GET("/todo/time/export", exportTimeEntriesHandler)
+
+	router.GET("/todo/tags", getTagsHandler)
+	router.POST("/todo/tags", createTagHandler)
+	router.PUT("/todo/tags/:tagId", updateTagHandler)
+	router.DELETE("/todo/tags/:tagId", deleteTagHandler)
+	router.PUT("/todo/item/:itemId/tags/:tagId", tagTodoItemHandler)
+	router.DELETE("/todo/item/:itemId/tags/:tagId", untagTodoItemHandler)
+
+	router.GET("/todo/series", getTodoSeriesHandler)
+	router.POST("/todo/series", createTodoSeriesHandler)
+	router.GET("/todo/series/:seriesId", getTodoSeriesByIdHandler)
+	router.PUT("/todo/series/:seriesId", updateTodoSeriesHandler)
+	router.DELETE("/todo/series/:seriesId", deleteTodoSeriesHandler)
+	router.PATCH("/todo/series/:seriesId/skip", skipTodoSeriesHandler)
+	router.GET("/todo/attachments/usage", getAttachmentUsageHandler)
+	router.GET("/todo/item/:itemId/attachments", getAttachmentsHandler)
+	router.POST("/todo/item/:itemId/attachments", uploadAttachmentHandler)
+	router.GET("/todo/item/:itemId/attachments/:attachmentId", downloadAttachmentHandler)
+	router.DELETE("/todo/item/:itemId/attachments/:attachmentId", deleteAttachmentHandler)
+	router.DELETE("/todo/item/:itemId", deleteTodoItemHandler)
 	return router
 }
 
@@ -48,6 +122,20 @@ func healthCheckHandler(ctx *gin.Context) {
 		"success": true, "message": "Running"})
 }
 
+// API handler used to redirect requests sent to the legacy /TODO
+// prefix to the lowercase /todo prefix. permanent redirects are
+// used so that clients keep the request method and body. the prefix
+// forwarded by the gateway (e.g. /api) is kept in the redirect
+func legacyPrefixHandler(ctx *gin.Context) {
+	prefix := ""
+	if forwarded := ctx.GetHeader("X-Forwarded-Prefix"); forwarded != "" {
+		prefix = strings.TrimSuffix(path.Clean("/"+forwarded), "/")
+	}
+	location := url.URL{Path: prefix + "/todo" + ctx.Param("path"), RawQuery: ctx.Request.URL.RawQuery}
+	log.Info(fmt.Sprintf("redirecting legacy request to %s", location.String()))
+	ctx.Redirect(http.StatusPermanentRedirect, location.String())
+}
+
 // API handler to generate a new TODO item for a given user
 func newTodoItemHandler(ctx *gin.Context) {
 	log.Info("received request for new todo item")
@@ -59,39 +147,151 @@ func newTodoItemHandler(ctx *gin.Context) {
 		return
 	}
 
+	if err := validateTodoItem(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid TODO item: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": invalidTodoItemMessage(err)})
+		return
+	}
+
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
-	if err := persistence.CreateTodoItem(uid, request); err != nil {
+	itemId, err := persistence.CreateTodoItem(uid, request)
+	if err != nil {
 		log.Error(fmt.Errorf("unable to create TODO item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "message": "Successfully created TODO item", "item_id": itemId})
+}
+
+// API handler to create a new TODO item from natural-language
+// input (e.g. "Call Anna tomorrow 5pm #family !high +Personal")
+func quickAddTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to quick-add todo item")
+	var request struct {
+		Input string `json:"input" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	uid := ctx.MustGet("uid").(string)
+	result, err := quickAddTodoItem(uid, request.Input)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to quick-add TODO item: %+v", err))
 		switch err {
-		case ErrInvalidTODOMetadata:
+		case ErrInvalidTODOTitle:
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
-				"success": false, "message": "Invalid item metadata"})
+				"success": false, "message": "Input does not contain an item title"})
 		default:
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
-				"success": false, "message": "Internal server error"})
+			abortWithProjectError(ctx, err)
 		}
 		return
 	}
 	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
-		"success": false, "message": "Successfully created TODO item"})
+		"success": true, "interpretation": result})
 }
 
 // API handler to retrieve TODO item(s) for a given user
 func getTodoItemsHandler(ctx *gin.Context) {
 	log.Info("received request to retrieve todo item(s)")
 
+	// parse optional due window, overdue, project and completion filters from query
+	filter := TODOItemFilter{Overdue: ctx.Query("overdue") == "true", Project: ctx.Query("project"),
+		Assigned: ctx.Query("assigned") == "true"}
+	if filter.Project != "" && filter.Project != "none" {
+		if _, err := uuid.Parse(filter.Project); err != nil {
+			log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid project ID"})
+			return
+		}
+	}
+	for param, target := range map[string]**time.Time{
+		"due_after":  &filter.DueAfter,
+		"due_before": &filter.DueBefore,
+	} {
+		value, ok := ctx.GetQuery(param)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to parse due window: %+v", err))
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid due window"})
+			return
+		}
+		*target = &ts
+	}
+	if value, ok := ctx.GetQuery("completed"); ok {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid completed filter"})
+			return
+		}
+		filter.Completed = &completed
+	}
+
+	// parse tag and metadata filters from query. metadata filters
+	// are given as meta.<key>=<value> query parameters
+	if tags, ok := ctx.GetQueryArray("tag"); ok {
+		normalized, err := normalizeTagNames(tags)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid tag filter"})
+			return
+		}
+		filter.Tags = normalized
+	}
+	for param, values := range ctx.Request.URL.Query() {
+		if !strings.HasPrefix(param, "meta.") {
+			continue
+		}
+		key := strings.TrimPrefix(param, "meta.")
+		if !metadataKeyPattern.MatchString(key) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid metadata filter"})
+			return
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = map[string]interface{}{}
+		}
+		filter.Metadata[key] = parseMetadataFilterValue(values[0])
+	}
+
+	// parse text query, sort order and page from query
+	filter.Query = ctx.Query("q")
+	filter.Sort = ctx.Query("sort")
+	filter.Descending = ctx.Query("order") == "desc"
+	filter.Cursor = ctx.Query("cursor")
+	if value, ok := ctx.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxTodoPageSize {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid page limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
-	items, err := persistence.GetTodoItems(uid)
+	page, err := persistence.GetTodoItems(uid, filter)
 	if err != nil {
-		log.Error(fmt.Errorf("unable to create TODO item: %+v", err))
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
-			"success": false, "message": "Internal server error"})
+		log.Error(fmt.Errorf("unable to retrieve TODO items: %+v", err))
+		abortWithProjectError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
-		"success": true, "items": items})
+		"success": true, "items": page.Items, "total": page.Total,
+		"next_cursor": page.NextCursor})
 }
 
 // API handler to complete a TODO item for a given user
@@ -107,10 +307,9 @@ func completeTodoItemHandler(ctx *gin.Context) {
 	}
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
-	if err := persistence.CompleteTodoItem(uid, itemId); err != nil {
+	if err := completeTodoItem(uid, itemId); err != nil {
 		log.Error(fmt.Errorf("unable to complete todo item: %+v", err))
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
-			"success": false, "message": "Internal server error"})
+		abortWithProjectError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
@@ -130,12 +329,1544 @@ func deleteTodoItemHandler(ctx *gin.Context) {
 	}
 	// retrieve user ID from context
 	uid := ctx.MustGet("uid").(string)
-	if err := persistence.DeleteTodoItem(uid, itemId); err != nil {
+	if err := deleteTodoItem(uid, itemId); err != nil {
 		log.Error(fmt.Errorf("unable to delete todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted TODO item"})
+}
+
+// function used to generate the error message returned
+// for TODO items that fail validation
+func invalidTodoItemMessage(err error) string {
+	switch err {
+	case ErrInvalidTODOTitle:
+		return "Invalid item title"
+	case ErrInvalidTODOPriority:
+		return "Invalid item priority"
+	case ErrInvalidTODOMetadata:
+		return "Invalid item metadata"
+	case ErrInvalidTag:
+		return "Invalid item tags"
+	default:
+		return "Invalid request body"
+	}
+}
+
+// API handler to retrieve a single TODO item for a given user
+func getTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve todo item")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	item, err := persistence.GetTodoItem(uid, itemId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "item": item})
+}
+
+// API handler to replace the title, content and metadata
+// (along with due dates and priority) of a TODO item
+func updateTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to update todo item")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	var request TODOItem
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	saveTodoItem(ctx, uid, itemId, request)
+}
+
+// API handler to partially update a TODO item. only
+// fields present in the request body are updated, and
+// due and reminder dates are cleared if set to null
+func patchTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to patch todo item")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	var request TODOItemPatch
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	if isEmptyTodoItemPatch(request) {
+		log.Error("received empty patch for todo item")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "No fields to update"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	item, err := persistence.GetTodoItem(uid, itemId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	saveTodoItem(ctx, uid, itemId, applyTodoItemPatch(item, request))
+}
+
+// function used to validate and store an updated TODO item. used
+// by both full (PUT) and partial (PATCH) update handlers
+func saveTodoItem(ctx *gin.Context, uid string, itemId uuid.UUID, item TODOItem) {
+	if err := validateTodoItem(&item); err != nil {
+		log.Error(fmt.Errorf("received invalid TODO item: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": invalidTodoItemMessage(err)})
+		return
+	}
+	if err := persistence.UpdateTodoItem(uid, itemId, item); err != nil {
+		log.Error(fmt.Errorf("unable to update todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated TODO item"})
+}
+
+// API handler to reopen a completed TODO item for a given user
+func reopenTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to reopen todo item")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.ReopenTodoItem(uid, itemId); err != nil {
+		log.Error(fmt.Errorf("unable to reopen todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully reopened TODO item"})
+}
+
+// API handler used to mark a TODO item as promoted into a habit.
+// items can only be promoted once
+func promoteTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to promote todo item")
+	setTodoItemPromoted(ctx, true)
+}
+
+// API handler used to release the promotion of a TODO item if
+// the habit that the item was promoted into could not be created
+func releaseTodoItemPromotionHandler(ctx *gin.Context) {
+	log.Info("received request to release todo item promotion")
+	setTodoItemPromoted(ctx, false)
+}
+
+// function used to set or release the promotion of a TODO item
+func setTodoItemPromoted(ctx *gin.Context, promoted bool) {
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetTodoItemPromoted(uid, itemId, promoted); err != nil {
+		log.Error(fmt.Errorf("unable to set todo item promotion: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated TODO item promotion"})
+}
+
+// API handler to retrieve the open TODO items of a user that can
+// be worked on next, along with all open items in dependency order
+func getActionableTodoItemsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve actionable todo items")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	actionable, plan, err := getActionableTodoItems(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve actionable todo items: %+v", err))
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
 			"success": false, "message": "Internal server error"})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
-		"success": true, "message": "Successfully deleted TODO item"})
+		"success": true, "items": actionable, "plan": plan})
+}
+
+// API handler to make a TODO item a subtask of another item
+func addTodoSubtaskHandler(ctx *gin.Context) {
+	log.Info("received request to add todo subtask")
+	modifyTodoDependency(ctx, "parentId", persistence.AddTodoSubtask,
+		"Successfully added TODO subtask")
+}
+
+// API handler to detach a TODO subtask from its parent
+func removeTodoSubtaskHandler(ctx *gin.Context) {
+	log.Info("received request to remove todo subtask")
+	modifyTodoDependency(ctx, "parentId", persistence.RemoveTodoSubtask,
+		"Successfully removed TODO subtask")
+}
+
+// API handler to mark a TODO item as blocked by another item
+func addTodoBlockerHandler(ctx *gin.Context) {
+	log.Info("received request to add todo blocker")
+	modifyTodoDependency(ctx, "blockerId", persistence.AddTodoBlocker,
+		"Successfully added TODO blocker")
+}
+
+// API handler to remove a blocker from a TODO item
+func removeTodoBlockerHandler(ctx *gin.Context) {
+	log.Info("received request to remove todo blocker")
+	modifyTodoDependency(ctx, "blockerId", persistence.RemoveTodoBlocker,
+		"Successfully removed TODO blocker")
+}
+
+// function used to add or remove dependencies between TODO items. the
+// related item is read from the given path parameter and the item ID
+// from the itemId path parameter
+func modifyTodoDependency(ctx *gin.Context, param string,
+	modify func(string, uuid.UUID, uuid.UUID) error, message string) {
+	relatedId, err := uuid.Parse(ctx.Param(param))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := modify(uid, relatedId, itemId); err != nil {
+		log.Error(fmt.Errorf("unable to modify todo dependency: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": message})
+}
+
+// function used to map TODO item, project, workflow, series, time
+// tracking and tag errors onto API responses
+func abortWithProjectError(ctx *gin.Context, err error) {
+	switch err {
+	case ErrTODOItemNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified TODO item"})
+	case ErrTODODependencyNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified TODO dependency"})
+	case ErrProjectNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified project"})
+	case ErrProjectItemNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "TODO item is not part of project"})
+	case ErrSeriesNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified TODO series"})
+	case ErrCollaboratorNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified collaborator"})
+	case ErrTODOPermissionDenied:
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"http_code": http.StatusForbidden,
+			"success": false, "message": "Insufficient permissions"})
+	case ErrTODOItemPromoted:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "TODO item has already been promoted"})
+	case ErrTODODependencyCycle:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "TODO dependency would create a cycle"})
+	case ErrProjectCycle:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "Project cannot be nested within itself"})
+	case ErrInvalidProjectTarget:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid target project"})
+	case ErrNoOpenOccurrence:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "TODO series has no open occurrence"})
+	case ErrInvalidSeries:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid TODO series"})
+	case ErrInvalidTODOPriority:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item priority"})
+	case ErrWorkflowNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Project has no workflow"})
+	case ErrInvalidWorkflow:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid workflow"})
+	case ErrWorkflowStateUnknown:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Unknown workflow state"})
+	case ErrInvalidTransition:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "Workflow transition is not allowed"})
+	case ErrTimeEntryNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified time entry"})
+	case ErrNoRunningTimer:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "No running timer"})
+	case ErrTimerRunning:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "Timer already running"})
+	case ErrInvalidTimeEntry:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid time entry"})
+	case ErrInvalidTimeReport:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid report group"})
+	case ErrTagNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified tag"})
+	case ErrTagExists:
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"http_code": http.StatusConflict,
+			"success": false, "message": "Tag already exists"})
+	case ErrInvalidTag:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid tag"})
+	case ErrInvalidTODOCursor:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid page cursor"})
+	case ErrInvalidTODOSort:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid sort field"})
+	case ErrInvalidTODOQuery:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid search query"})
+	case ErrInvalidTODOMetadata:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item metadata"})
+	case ErrInvalidCollaborator:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid collaborator"})
+	case ErrInvalidCollaboratorRole:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid collaborator role"})
+	case ErrInvalidAssignee:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid assignee"})
+	case ErrAttachmentNotFound:
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"http_code": http.StatusNotFound,
+			"success": false, "message": "Cannot find specified attachment"})
+	case ErrInvalidAttachment:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid attachment"})
+	case ErrAttachmentTooLarge:
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"http_code": http.StatusRequestEntityTooLarge,
+			"success": false, "message": "Attachment exceeds maximum file size"})
+	case ErrAttachmentQuotaExceeded:
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"http_code": http.StatusRequestEntityTooLarge,
+			"success": false, "message": "Attachment storage quota exceeded"})
+	default:
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"http_code": http.StatusInternalServerError,
+			"success": false, "message": "Internal server error"})
+	}
+}
+
+// API handler to retrieve the projects of a given user
+func getProjectsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve projects")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	projects, err := persistence.GetProjects(uid, ctx.Query("archived") == "true")
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve projects: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "projects": projects})
+}
+
+// API handler to create a new project for a given user
+func createProjectHandler(ctx *gin.Context) {
+	log.Info("received request to create project")
+	var request Project
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	projectId, err := persistence.CreateProject(uid, request)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "project_id": projectId})
+}
+
+// API handler to update the name, description and parent of a project
+func updateProjectHandler(ctx *gin.Context) {
+	log.Info("received request to update project")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	var request Project
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.UpdateProject(uid, projectId, request); err != nil {
+		log.Error(fmt.Errorf("unable to update project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated project"})
+}
+
+// API handler to delete a project. the items of the project are
+// deleted if mode is set to cascade, else they are reassigned to
+// the target project (or the parent of the deleted project)
+func deleteProjectHandler(ctx *gin.Context) {
+	log.Info("received request to delete project")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	mode := ctx.DefaultQuery("mode", "reassign")
+	if mode != "reassign" && mode != "cascade" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid delete mode"})
+		return
+	}
+	var targetId *uuid.UUID
+	if target, ok := ctx.GetQuery("target"); ok {
+		id, err := uuid.Parse(target)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to parse target project ID: %+v", err))
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid target project"})
+			return
+		}
+		targetId = &id
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := deleteProject(uid, projectId, mode == "cascade", targetId); err != nil {
+		log.Error(fmt.Errorf("unable to delete project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted project"})
+}
+
+// API handler to archive a project
+func archiveProjectHandler(ctx *gin.Context) {
+	log.Info("received request to archive project")
+	setProjectArchived(ctx, true)
+}
+
+// API handler to unarchive a project
+func unarchiveProjectHandler(ctx *gin.Context) {
+	log.Info("received request to unarchive project")
+	setProjectArchived(ctx, false)
+}
+
+// function used to set the archived state of a project
+func setProjectArchived(ctx *gin.Context, archived bool) {
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetProjectArchived(uid, projectId, archived); err != nil {
+		log.Error(fmt.Errorf("unable to set project archived state: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated project"})
+}
+
+// API handler to reorder the items within a project
+func reorderProjectItemsHandler(ctx *gin.Context) {
+	log.Info("received request to reorder project items")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	var request struct {
+		ItemIds []uuid.UUID `json:"item_ids" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.ReorderProjectItems(uid, projectId, request.ItemIds); err != nil {
+		log.Error(fmt.Errorf("unable to reorder project items: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully reordered project items"})
+}
+
+// API handler to move a TODO item into a project. items are
+// placed at the (optional) position given as query parameter
+func moveTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to move todo item to project")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	var position *int64
+	if value, ok := ctx.GetQuery("position"); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid position"})
+			return
+		}
+		position = &parsed
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.MoveTodoItemToProject(uid, projectId, itemId, position); err != nil {
+		log.Error(fmt.Errorf("unable to move todo item to project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully moved TODO item"})
+}
+
+// API handler to remove a TODO item from a project
+func removeTodoItemFromProjectHandler(ctx *gin.Context) {
+	log.Info("received request to remove todo item from project")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.RemoveTodoItemFromProject(uid, projectId, itemId); err != nil {
+		log.Error(fmt.Errorf("unable to remove todo item from project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully removed TODO item from project"})
+}
+
+// API handler to retrieve the collaborators of a project
+func getProjectCollaboratorsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve project collaborators")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	collaborators, err := persistence.GetProjectCollaborators(uid, projectId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve project collaborators: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "collaborators": collaborators})
+}
+
+// API handler to share a project with another user as viewer or editor
+func shareProjectHandler(ctx *gin.Context) {
+	log.Info("received request to share project")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	var request struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	if err := validateCollaboratorRole(request.Role); err != nil {
+		abortWithProjectError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	collaborator := ctx.Param("collaborator")
+	if collaborator == uid {
+		abortWithProjectError(ctx, ErrInvalidCollaborator)
+		return
+	}
+	if err := persistence.ShareProject(uid, projectId, collaborator, request.Role); err != nil {
+		log.Error(fmt.Errorf("unable to share project: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully shared project"})
+}
+
+// API handler to remove a collaborator from a project
+func removeProjectCollaboratorHandler(ctx *gin.Context) {
+	log.Info("received request to remove project collaborator")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.RemoveProjectCollaborator(uid, projectId, ctx.Param("collaborator")); err != nil {
+		log.Error(fmt.Errorf("unable to remove project collaborator: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully removed collaborator"})
+}
+
+// API handler to assign a TODO item to a user
+func assignTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to assign todo item")
+	assignee := ctx.Param("assignee")
+	assignTodoItem(ctx, &assignee)
+}
+
+// API handler to remove the assignee of a TODO item
+func unassignTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to unassign todo item")
+	assignTodoItem(ctx, nil)
+}
+
+// function used to set the assignee of a TODO item
+func assignTodoItem(ctx *gin.Context, assignee *string) {
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.AssignTodoItem(uid, itemId, assignee); err != nil {
+		log.Error(fmt.Errorf("unable to assign todo item: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated TODO item assignee"})
+}
+
+// API handler to retrieve the TODO series of a given user
+func getTodoSeriesHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve todo series")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	series, err := persistence.GetTodoSeries(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve todo series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "series": series})
+}
+
+// API handler to create a new TODO series. the first occurrence
+// of the series is created along with the series
+func createTodoSeriesHandler(ctx *gin.Context) {
+	log.Info("received request to create todo series")
+	var request TodoSeries
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	if err := validateTodoSeries(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid TODO series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	due := getFirstOccurrence(request)
+	seriesId, err := persistence.CreateTodoSeries(uid, request, due)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create todo series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "series_id": seriesId, "next_due": due})
+}
+
+// API handler to retrieve a single TODO series
+func getTodoSeriesByIdHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve todo series")
+
+	seriesId, err := uuid.Parse(ctx.Param("seriesId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse series ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid series ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	series, err := getTodoSeries(uid, seriesId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve todo series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "series": series})
+}
+
+// API handler to update the definition of a TODO series
+func updateTodoSeriesHandler(ctx *gin.Context) {
+	log.Info("received request to update todo series")
+
+	seriesId, err := uuid.Parse(ctx.Param("seriesId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse series ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid series ID"})
+		return
+	}
+	var request TodoSeries
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	if err := validateTodoSeries(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid TODO series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.UpdateTodoSeries(uid, seriesId, request); err != nil {
+		log.Error(fmt.Errorf("unable to update todo series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated TODO series"})
+}
+
+// API handler to skip the open occurrence of a TODO series. the
+// due date of the occurrence is rolled forward to the next occurrence
+func skipTodoSeriesHandler(ctx *gin.Context) {
+	log.Info("received request to skip todo series occurrence")
+
+	seriesId, err := uuid.Parse(ctx.Param("seriesId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse series ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid series ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	next, err := skipSeriesOccurrence(uid, seriesId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to skip todo series occurrence: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "next_due": next})
+}
+
+// API handler to delete a TODO series. existing occurrences
+// of the series are kept as regular TODO items
+func deleteTodoSeriesHandler(ctx *gin.Context) {
+	log.Info("received request to delete todo series")
+
+	seriesId, err := uuid.Parse(ctx.Param("seriesId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse series ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid series ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteTodoSeries(uid, seriesId); err != nil {
+		log.Error(fmt.Errorf("unable to delete todo series: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted TODO series"})
+}
+
+// API handler to retrieve the tags of a given user
+func getTagsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve tags")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	tags, err := persistence.GetTags(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve tags: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "tags": tags})
+}
+
+// API handler to create a new tag for a given user
+func createTagHandler(ctx *gin.Context) {
+	log.Info("received request to create tag")
+	var request Tag
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	name, err := normalizeTagName(request.TagName)
+	if err != nil {
+		abortWithProjectError(ctx, err)
+		return
+	}
+	request.TagName = name
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	tagId, err := persistence.CreateTag(uid, request)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create tag: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "tag_id": tagId})
+}
+
+// API handler to rename (and recolour) a tag
+func updateTagHandler(ctx *gin.Context) {
+	log.Info("received request to update tag")
+
+	tagId, err := uuid.Parse(ctx.Param("tagId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse tag ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid tag ID"})
+		return
+	}
+	var request Tag
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	name, err := normalizeTagName(request.TagName)
+	if err != nil {
+		abortWithProjectError(ctx, err)
+		return
+	}
+	request.TagName = name
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.UpdateTag(uid, tagId, request); err != nil {
+		log.Error(fmt.Errorf("unable to update tag: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated tag"})
+}
+
+// API handler to delete a tag. the tag is removed from all items
+func deleteTagHandler(ctx *gin.Context) {
+	log.Info("received request to delete tag")
+
+	tagId, err := uuid.Parse(ctx.Param("tagId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse tag ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid tag ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteTag(uid, tagId); err != nil {
+		log.Error(fmt.Errorf("unable to delete tag: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted tag"})
+}
+
+// API handler to attach a tag to a TODO item
+func tagTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to tag todo item")
+	setTodoItemTagged(ctx, true)
+}
+
+// API handler to remove a tag from a TODO item
+func untagTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to untag todo item")
+	setTodoItemTagged(ctx, false)
+}
+
+// function used to attach or remove a tag from a TODO item
+func setTodoItemTagged(ctx *gin.Context, tagged bool) {
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	tagId, err := uuid.Parse(ctx.Param("tagId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse tag ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid tag ID"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if tagged {
+		err = persistence.TagTodoItem(uid, itemId, tagId)
+	} else {
+		err = persistence.UntagTodoItem(uid, itemId, tagId)
+	}
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update todo item tags: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated TODO item tags"})
+}
+
+// API handler to retrieve the workflow of a project
+func getProjectWorkflowHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve project workflow")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	workflow, err := persistence.GetProjectWorkflow(uid, projectId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve project workflow: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "workflow": workflow})
+}
+
+// API handler to set (or replace) the workflow of a project
+func setProjectWorkflowHandler(ctx *gin.Context) {
+	log.Info("received request to set project workflow")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	var request Workflow
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+	if err := validateWorkflow(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid workflow: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.SetProjectWorkflow(uid, projectId, request); err != nil {
+		log.Error(fmt.Errorf("unable to set project workflow: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully updated project workflow"})
+}
+
+// API handler to remove the workflow of a project
+func deleteProjectWorkflowHandler(ctx *gin.Context) {
+	log.Info("received request to delete project workflow")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteProjectWorkflow(uid, projectId); err != nil {
+		log.Error(fmt.Errorf("unable to delete project workflow: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted project workflow"})
+}
+
+// API handler to retrieve the board of a project. items are grouped
+// by workflow state and WIP limit violations are returned as warnings
+func getProjectBoardHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve project board")
+
+	projectId, err := uuid.Parse(ctx.Param("projectId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse project ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid project ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	board, err := getProjectBoard(uid, projectId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve project board: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "board": board})
+}
+
+// API handler to move a TODO item into another workflow state
+func transitionTodoItemHandler(ctx *gin.Context) {
+	log.Info("received request to change todo item state")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	var request struct {
+		State string `json:"state" binding:"required"`
+	}
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	change, err := transitionTodoItem(uid, itemId, strings.TrimSpace(request.State))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to change todo item state: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "change": change})
+}
+
+// API handler to retrieve the state changes of a TODO item
+func getTodoItemHistoryHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve todo item history")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	changes, err := persistence.GetTodoItemHistory(uid, itemId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve todo item history: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "history": changes})
+}
+
+// function used to parse the optional (RFC3339) from and to query
+// parameters used to select time entries. the request is aborted
+// if either of the parameters is invalid
+func parseTimeWindow(ctx *gin.Context) (*time.Time, *time.Time, bool) {
+	window := []*time.Time{nil, nil}
+	for i, param := range []string{"from", "to"} {
+		value, ok := ctx.GetQuery(param)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Error(fmt.Errorf("unable to parse time window: %+v", err))
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+				"success": false, "message": "Invalid time window"})
+			return nil, nil, false
+		}
+		window[i] = &ts
+	}
+	return window[0], window[1], true
+}
+
+// API handler to retrieve the running timer of a given user
+func getRunningTimerHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve running timer")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entry, err := persistence.GetRunningTimer(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve running timer: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "entry": entry})
+}
+
+// API handler to start a timer on a TODO item. users
+// can only have a single running timer at any time
+func startTimerHandler(ctx *gin.Context) {
+	log.Info("received request to start timer")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entry, err := persistence.StartTimer(uid, itemId, ctx.Query("note"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to start timer: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "entry": entry})
+}
+
+// API handler to stop the running timer of a given user
+func stopTimerHandler(ctx *gin.Context) {
+	log.Info("received request to stop timer")
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entry, err := persistence.StopTimer(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to stop timer: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "entry": entry})
+}
+
+// API handler to retrieve the time entries of a user for a TODO item
+func getTodoItemTimeEntriesHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve todo item time entries")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entries, err := persistence.GetTimeEntries(uid, TimeEntryFilter{ItemId: &itemId})
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve time entries: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "entries": entries})
+}
+
+// API handler to log a manual time entry on a TODO item
+func logTimeEntryHandler(ctx *gin.Context) {
+	log.Info("received request to log time entry")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	var request TimeEntry
+	if err := ctx.ShouldBind(&request); err != nil {
+		log.Error(fmt.Errorf("received invalid request: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid request body"})
+		return
+	}
+
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entryId, err := logTimeEntry(uid, itemId, request)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to log time entry: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "entry_id": entryId})
+}
+
+// API handler to delete a time entry
+func deleteTimeEntryHandler(ctx *gin.Context) {
+	log.Info("received request to delete time entry")
+
+	entryId, err := uuid.Parse(ctx.Param("entryId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse entry ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid entry ID"})
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	if err := persistence.DeleteTimeEntry(uid, entryId); err != nil {
+		log.Error(fmt.Errorf("unable to delete time entry: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted time entry"})
+}
+
+// API handler to retrieve a time report for a given user. time
+// is grouped by project, tag or day (defaulting to project)
+func getTimeReportHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve time report")
+
+	from, to, ok := parseTimeWindow(ctx)
+	if !ok {
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	report, err := getTimeReport(uid, from, to, ctx.DefaultQuery("group", "project"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to generate time report: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "report": report})
+}
+
+// API handler to export the time entries of a given user as CSV
+func exportTimeEntriesHandler(ctx *gin.Context) {
+	log.Info("received request to export time entries")
+
+	from, to, ok := parseTimeWindow(ctx)
+	if !ok {
+		return
+	}
+	// retrieve user ID from context
+	uid := ctx.MustGet("uid").(string)
+	entries, err := persistence.GetTimeEntries(uid, TimeEntryFilter{From: from, To: to})
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve time entries: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=time-entries.csv")
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(http.StatusOK)
+	if err := writeTimeEntriesCSV(ctx.Writer, entries); err != nil {
+		log.Error(fmt.Errorf("unable to write time entries: %+v", err))
+	}
+}
+
+// function used to parse the item and attachment IDs from
+// request path. requests with invalid IDs are aborted
+func parseAttachmentIds(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return itemId, uuid.Nil, false
+	}
+	attachmentId, err := uuid.Parse(ctx.Param("attachmentId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse attachment ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid attachment ID"})
+		return itemId, attachmentId, false
+	}
+	return itemId, attachmentId, true
+}
+
+// API handler to retrieve the attachment storage usage of a user
+func getAttachmentUsageHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve attachment usage")
+
+	uid := ctx.MustGet("uid").(string)
+	usage, err := persistence.GetAttachmentUsage(uid)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve attachment usage: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK, "success": true,
+		"usage": usage, "quota": attachmentQuota, "max_file_size": maxAttachmentSize})
+}
+
+// API handler to retrieve the attachments of a TODO item
+func getAttachmentsHandler(ctx *gin.Context) {
+	log.Info("received request to retrieve attachments")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+
+	uid := ctx.MustGet("uid").(string)
+	attachments, err := persistence.GetAttachments(uid, itemId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve attachments: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "attachments": attachments})
+}
+
+// API handler to upload an attachment to a TODO item. files are
+// sent as multipart forms with the file stored in the file field
+func uploadAttachmentHandler(ctx *gin.Context) {
+	log.Info("received request to upload attachment")
+
+	itemId, err := uuid.Parse(ctx.Param("itemId"))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to parse item ID: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid item ID"})
+		return
+	}
+	// limit request body to maximum attachment size (plus
+	// some allowance for multipart boundaries and headers)
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxAttachmentSize+(1<<20))
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		log.Error(fmt.Errorf("received invalid attachment upload: %+v", err))
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"http_code": http.StatusBadRequest,
+			"success": false, "message": "Invalid attachment upload"})
+		return
+	}
+
+	uid := ctx.MustGet("uid").(string)
+	attachment, err := createAttachment(uid, itemId, header)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create attachment: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
+		"success": true, "attachment": attachment})
+}
+
+// API handler to download the content of an attachment
+func downloadAttachmentHandler(ctx *gin.Context) {
+	log.Info("received request to download attachment")
+	itemId, attachmentId, ok := parseAttachmentIds(ctx)
+	if !ok {
+		return
+	}
+
+	uid := ctx.MustGet("uid").(string)
+	attachment, content, err := openAttachment(uid, itemId, attachmentId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to open attachment: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	defer content.Close()
+
+	// attachments are always served as downloads and browsers
+	// are prevented from sniffing a different content type
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	if len(disposition) == 0 {
+		disposition = "attachment"
+	}
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content,
+		map[string]string{"Content-Disposition": disposition, "X-Content-Type-Options": "nosniff",
+			"ETag": fmt.Sprintf(`"%s"`, attachment.Digest)})
+}
+
+// API handler to delete an attachment of a TODO item
+func deleteAttachmentHandler(ctx *gin.Context) {
+	log.Info("received request to delete attachment")
+	itemId, attachmentId, ok := parseAttachmentIds(ctx)
+	if !ok {
+		return
+	}
+
+	uid := ctx.MustGet("uid").(string)
+	if err := deleteAttachment(uid, itemId, attachmentId); err != nil {
+		log.Error(fmt.Errorf("unable to delete attachment: %+v", err))
+		abortWithProjectError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+		"success": true, "message": "Successfully deleted attachment"})
 }
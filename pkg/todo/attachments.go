@@ -0,0 +1,195 @@
+package todo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+const (
+	// define default limits for attachments
+	defaultMaxAttachmentSize = 10 << 20
+	defaultAttachmentQuota   = 100 << 20
+)
+
+var (
+	// define blob store used to store attachment content
+	blobs utils.BlobStore
+	// define maximum size of single attachments and
+	// maximum total size of attachments per user
+	maxAttachmentSize int64 = defaultMaxAttachmentSize
+	attachmentQuota   int64 = defaultAttachmentQuota
+)
+
+// struct used to return attachment content along with the
+// bytes already read to detect the content type
+type attachmentContent struct {
+	io.Reader
+	io.Closer
+}
+
+// function used to set blob store and limits used for attachments
+func SetBlobStore(store utils.BlobStore, maxFileSize, maxUserSize int64) {
+	blobs = store
+	maxAttachmentSize, attachmentQuota = maxFileSize, maxUserSize
+}
+
+// function used to clean up blobs of deleted attachments. blobs
+// are only deleted if they are not referenced by other attachments
+func cleanupBlobs(digests []string) {
+	if len(digests) == 0 {
+		return
+	}
+	if err := persistence.DeleteUnreferencedBlobs(digests, blobs.Delete); err != nil {
+		log.Error(fmt.Errorf("unable to clean up blobs: %+v", err))
+	}
+}
+
+// function used to determine the content type of attachment content.
+// the content type is always detected from the content itself and
+// never taken from the client. the returned reader includes the bytes
+// read to detect the content type
+func detectAttachmentContentType(content io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", content, err
+	}
+	head = head[:n]
+	return http.DetectContentType(head), io.MultiReader(bytes.NewReader(head), content), nil
+}
+
+// function used to attach an uploaded file to a todo item. files
+// larger than the maximum attachment size or that would exceed the
+// quota of the user are rejected before they are stored
+func createAttachment(uid string, itemId uuid.UUID, header *multipart.FileHeader) (Attachment, error) {
+	fileName := strings.TrimSpace(filepath.Base(filepath.Clean("/" + header.Filename)))
+	if header.Size <= 0 || len(fileName) == 0 || fileName == "/" {
+		return Attachment{}, ErrInvalidAttachment
+	}
+	if header.Size > maxAttachmentSize {
+		return Attachment{}, ErrAttachmentTooLarge
+	}
+	usage, err := persistence.GetAttachmentUsage(uid)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if usage+header.Size > attachmentQuota {
+		return Attachment{}, ErrAttachmentQuotaExceeded
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		log.Error(fmt.Errorf("unable to open uploaded file: %+v", err))
+		return Attachment{}, err
+	}
+	defer file.Close()
+
+	// the digest is computed before the attachment is created so that
+	// the blob is referenced (and cannot be cleaned up by other replicas)
+	// by the time it is written to the blob store
+	contentType, content, err := detectAttachmentContentType(file)
+	if err != nil {
+		return Attachment{}, err
+	}
+	digest, size, err := utils.BlobDigest(io.LimitReader(content, maxAttachmentSize+1))
+	if err != nil {
+		return Attachment{}, err
+	}
+	if size > maxAttachmentSize {
+		return Attachment{}, ErrAttachmentTooLarge
+	}
+	attachment, err := persistence.CreateAttachment(uid, Attachment{
+		ItemId:      itemId,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Digest:      digest,
+	}, attachmentQuota)
+	if err != nil {
+		return attachment, err
+	}
+
+	// store blob and remove the attachment again if the blob cannot be stored
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		deleteAttachment(uid, itemId, attachment.AttachmentId)
+		return Attachment{}, err
+	}
+	stored, _, err := blobs.Put(io.LimitReader(file, maxAttachmentSize))
+	if err != nil {
+		deleteAttachment(uid, itemId, attachment.AttachmentId)
+		return Attachment{}, err
+	}
+	if stored != digest {
+		log.Error(fmt.Sprintf("uploaded file changed while storing blob %s", digest))
+		deleteAttachment(uid, itemId, attachment.AttachmentId)
+		cleanupBlobs([]string{stored})
+		return Attachment{}, ErrInvalidAttachment
+	}
+	return attachment, nil
+}
+
+// function used to open the content of an attachment. the content
+// type of the attachment is detected from its content. the returned
+// reader must be closed by the caller
+func openAttachment(uid string, itemId, attachmentId uuid.UUID) (Attachment, io.ReadCloser, error) {
+	attachment, err := persistence.GetAttachment(uid, itemId, attachmentId)
+	if err != nil {
+		return attachment, nil, err
+	}
+	blob, err := blobs.Get(attachment.Digest)
+	if err == utils.ErrBlobNotFound {
+		log.Error(fmt.Sprintf("blob %s of attachment %s is missing", attachment.Digest, attachmentId))
+		return attachment, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return attachment, nil, err
+	}
+	contentType, content, err := detectAttachmentContentType(blob)
+	if err != nil {
+		blob.Close()
+		return attachment, nil, err
+	}
+	attachment.ContentType = contentType
+	return attachment, attachmentContent{Reader: content, Closer: blob}, nil
+}
+
+// function used to delete an attachment and its blob
+func deleteAttachment(uid string, itemId, attachmentId uuid.UUID) error {
+	digest, err := persistence.DeleteAttachment(uid, itemId, attachmentId)
+	if err != nil {
+		return err
+	}
+	cleanupBlobs([]string{digest})
+	return nil
+}
+
+// function used to delete a todo item and the blobs of its attachments
+func deleteTodoItem(uid string, itemId uuid.UUID) error {
+	digests, err := persistence.DeleteTodoItem(uid, itemId)
+	if err != nil {
+		return err
+	}
+	cleanupBlobs(digests)
+	return nil
+}
+
+// function used to delete a project and the blobs of any
+// attachments deleted along with the items of the project
+func deleteProject(uid string, projectId uuid.UUID, cascade bool, targetId *uuid.UUID) error {
+	digests, err := persistence.DeleteProject(uid, projectId, cascade, targetId)
+	if err != nil {
+		return err
+	}
+	cleanupBlobs(digests)
+	return nil
+}
@@ -0,0 +1,128 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// function used to evaluate the progress of a TODO item. items
+// with subtasks roll up the progress of all their (nested) subtasks
+func getTodoItemProgress(item TODOItem) float64 {
+	if item.Subtasks > 0 {
+		return float64(item.CompletedSubtasks) / float64(item.Subtasks)
+	}
+	if item.Completed {
+		return 1
+	}
+	return 0
+}
+
+// function used to determine the rank of a TODO priority.
+// higher priorities have higher ranks
+func getPriorityRank(priority string) int {
+	for i, p := range todoPriorities {
+		if p == priority {
+			return i
+		}
+	}
+	return 0
+}
+
+// function used to determine if a TODO item should be worked on
+// before another item when both are available. items are ordered
+// by priority, then by due date and finally by creation date
+func todoItemBefore(a, b TODOItem) bool {
+	if rankA, rankB := getPriorityRank(a.Priority), getPriorityRank(b.Priority); rankA != rankB {
+		return rankA > rankB
+	}
+	if a.DueAt != nil && b.DueAt != nil && !a.DueAt.Equal(*b.DueAt) {
+		return a.DueAt.Before(*b.DueAt)
+	}
+	if (a.DueAt == nil) != (b.DueAt == nil) {
+		return a.DueAt != nil
+	}
+	return a.Created.Before(b.Created)
+}
+
+// function used to order open TODO items topologically. items must
+// be completed after all of their blockers and parents after all of
+// their subtasks. items that are available at the same time are
+// ordered by priority and due date
+func orderTodoItems(items []TODOItem) []TODOItem {
+	open := map[uuid.UUID]TODOItem{}
+	for _, item := range items {
+		if !item.Completed {
+			open[item.ItemId] = item
+		}
+	}
+
+	// evaluate number of open dependencies and dependent items
+	dependencies := map[uuid.UUID]int{}
+	dependents := map[uuid.UUID][]uuid.UUID{}
+	for id, item := range open {
+		for _, blocker := range item.BlockedBy {
+			if _, ok := open[blocker]; ok {
+				dependencies[id]++
+				dependents[blocker] = append(dependents[blocker], id)
+			}
+		}
+		if item.ParentId != nil {
+			if _, ok := open[*item.ParentId]; ok {
+				dependencies[*item.ParentId]++
+				dependents[id] = append(dependents[id], *item.ParentId)
+			}
+		}
+	}
+
+	available := []TODOItem{}
+	for id, item := range open {
+		if dependencies[id] == 0 {
+			available = append(available, item)
+		}
+	}
+	ordered := []TODOItem{}
+	for len(available) > 0 {
+		sort.SliceStable(available, func(i, j int) bool {
+			return todoItemBefore(available[i], available[j])
+		})
+		next := available[0]
+		available = available[1:]
+		ordered = append(ordered, next)
+		for _, id := range dependents[next.ItemId] {
+			dependencies[id]--
+			if dependencies[id] == 0 {
+				available = append(available, open[id])
+			}
+		}
+	}
+	if len(ordered) < len(open) {
+		log.Warn(fmt.Sprintf("unable to order %d TODO item(s): dependency cycle detected",
+			len(open)-len(ordered)))
+	}
+	return ordered
+}
+
+// function used to retrieve the open TODO items of a user in the
+// order in which they can be completed. the items that can be worked
+// on immediately are returned along with the full plan
+func getActionableTodoItems(uid string) ([]TODOItem, []TODOItem, error) {
+	open := false
+	page, err := persistence.GetTodoItems(uid, TODOItemFilter{Completed: &open})
+	if err != nil {
+		return nil, nil, err
+	}
+	plan := orderTodoItems(page.Items)
+
+	// items are actionable if they have no open blockers
+	// and all of their subtasks have been completed
+	actionable := []TODOItem{}
+	for _, item := range plan {
+		if !item.Blocked && item.CompletedSubtasks == item.Subtasks {
+			actionable = append(actionable, item)
+		}
+	}
+	return actionable, plan, nil
+}
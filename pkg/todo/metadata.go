@@ -0,0 +1,121 @@
+package todo
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+const (
+	// define prefix used to store metadata as typed item properties
+	metadataPrefix = "meta_"
+	// define limits for metadata and tags of TODO items
+	maxMetadataFields      = 32
+	maxMetadataValueLength = 1024
+	maxTagNameLength       = 64
+)
+
+// define pattern metadata keys must match. keys are stored as
+// node properties and are therefore restricted to identifiers
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+
+// function used to validate the metadata of TODO items and series.
+// metadata values must be strings, numbers or booleans. numbers are
+// converted to floats so that they can be compared when filtering
+func validateTodoMetadata(metadata map[string]interface{}) error {
+	if len(metadata) > maxMetadataFields {
+		return ErrInvalidTODOMetadata
+	}
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) {
+			return ErrInvalidTODOMetadata
+		}
+		switch v := value.(type) {
+		case string:
+			if len(v) > maxMetadataValueLength {
+				return ErrInvalidTODOMetadata
+			}
+		case bool, float64:
+		case int:
+			metadata[key] = float64(v)
+		case int64:
+			metadata[key] = float64(v)
+		default:
+			return ErrInvalidTODOMetadata
+		}
+	}
+	return nil
+}
+
+// function used to convert metadata into the typed
+// node properties used to store metadata on TODO items
+func metadataProperties(metadata map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for key, value := range metadata {
+		properties[metadataPrefix+key] = value
+	}
+	return properties
+}
+
+// function used to convert metadata properties returned from the
+// graph into a metadata map. items that have not been updated since
+// metadata was stored as a JSON string are converted from the string
+func parseTodoMetadata(legacy interface{}, properties interface{}) map[string]interface{} {
+	metadata := map[string]interface{}{}
+	if value, ok := legacy.(string); ok {
+		json.Unmarshal([]byte(value), &metadata)
+	}
+	for _, property := range properties.([]interface{}) {
+		pair := property.([]interface{})
+		metadata[pair[0].(string)] = pair[1]
+	}
+	return metadata
+}
+
+// function used to parse metadata filter values given as query
+// parameters. values are parsed as booleans or numbers where possible
+// and can be quoted to force them to be compared as strings
+func parseMetadataFilterValue(value string) interface{} {
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return unquoted
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// function used to normalize tag names. tag names are trimmed
+// and converted to lowercase so that tags are matched by name
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 || len(name) > maxTagNameLength {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
+
+// function used to normalize a list of tag names. duplicate
+// tags are removed from the list
+func normalizeTagNames(names []string) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+	tags := []string{}
+	for _, name := range names {
+		tag, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !utils.StringSliceContains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -16,7 +16,51 @@ import (
 var (
 	// define custom errors
 	ErrTODOItemNotFound    = errors.New("cannot find specified TODO item")
+	ErrTODOItemPromoted    = errors.New("TODO item has already been promoted")
 	ErrInvalidTODOMetadata = errors.New("invalid TODO metadata")
+	ErrInvalidTODOPriority = errors.New("invalid TODO priority")
+	ErrInvalidTODOTitle    = errors.New("invalid TODO title")
+
+	ErrTODODependencyCycle    = errors.New("TODO dependency would create a cycle")
+	ErrTODODependencyNotFound = errors.New("cannot find specified TODO dependency")
+
+	ErrProjectNotFound      = errors.New("cannot find specified project")
+	ErrProjectCycle         = errors.New("project cannot be nested within itself")
+	ErrInvalidProjectTarget = errors.New("invalid target project")
+	ErrProjectItemNotFound  = errors.New("TODO item is not part of project")
+
+	ErrTODOPermissionDenied    = errors.New("insufficient permissions")
+	ErrCollaboratorNotFound    = errors.New("cannot find specified collaborator")
+	ErrInvalidCollaborator     = errors.New("invalid collaborator")
+	ErrInvalidCollaboratorRole = errors.New("invalid collaborator role")
+	ErrInvalidAssignee         = errors.New("invalid TODO assignee")
+	ErrInvalidTODOCursor       = errors.New("invalid TODO cursor")
+	ErrInvalidTODOSort         = errors.New("invalid TODO sort field")
+	ErrInvalidTODOQuery        = errors.New("invalid TODO search query")
+
+	ErrWorkflowNotFound     = errors.New("project has no workflow")
+	ErrInvalidWorkflow      = errors.New("invalid workflow")
+	ErrWorkflowStateUnknown = errors.New("cannot find specified workflow state")
+	ErrInvalidTransition    = errors.New("workflow transition is not allowed")
+
+	ErrTimeEntryNotFound = errors.New("cannot find specified time entry")
+	ErrInvalidTimeEntry  = errors.New("invalid time entry")
+	ErrTimerRunning      = errors.New("user already has a running timer")
+	ErrNoRunningTimer    = errors.New("user has no running timer")
+	ErrInvalidTimeReport = errors.New("invalid time report group")
+
+	ErrTagNotFound = errors.New("cannot find specified tag")
+	ErrTagExists   = errors.New("tag already exists")
+	ErrInvalidTag  = errors.New("invalid tag")
+
+	ErrSeriesNotFound   = errors.New("cannot find specified TODO series")
+	ErrInvalidSeries    = errors.New("invalid TODO series")
+	ErrNoOpenOccurrence = errors.New("TODO series has no open occurrence")
+
+	ErrAttachmentNotFound      = errors.New("cannot find specified attachment")
+	ErrInvalidAttachment       = errors.New("invalid attachment")
+	ErrAttachmentTooLarge      = errors.New("attachment exceeds maximum file size")
+	ErrAttachmentQuotaExceeded = errors.New("attachment storage quota exceeded")
 )
 
 type GraphPersistence struct {
@@ -43,53 +87,575 @@ type TODOItem struct {
 	ItemContent string                 `json:"item_content" binding:"required"`
 	Created     time.Time              `json:"created"`
 	Completed   bool                   `json:"completed"`
+	CompletedAt *time.Time             `json:"completed_at"`
 	Metadata    map[string]interface{} `json:"metadata" binding:"required"`
+	DueAt       *time.Time             `json:"due_at"`
+	RemindAt    *time.Time             `json:"remind_at"`
+	Priority    string                 `json:"priority"`
+	Overdue     bool                   `json:"overdue"`
+
+	ParentId          *uuid.UUID  `json:"parent_id"`
+	BlockedBy         []uuid.UUID `json:"blocked_by"`
+	Blocked           bool        `json:"blocked"`
+	Subtasks          int64       `json:"subtasks"`
+	CompletedSubtasks int64       `json:"completed_subtasks"`
+	Progress          float64     `json:"progress"`
+
+	ProjectId *uuid.UUID `json:"project_id"`
+	Position  *int64     `json:"position"`
+
+	CreatedBy   string     `json:"created_by"`
+	UpdatedBy   *string    `json:"updated_by"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	CompletedBy *string    `json:"completed_by"`
+	AssignedTo  *string    `json:"assigned_to"`
+
+	SeriesId *uuid.UUID `json:"series_id"`
+	// tags of the item. tags are only replaced on
+	// updates if a list of tags is given
+	Tags []string `json:"tags"`
+	// workflow state of the item. only set for
+	// items in projects with a workflow
+	State *string `json:"state"`
+}
+
+// struct used to partially update TODO items. fields
+// that are not set are left unchanged
+type TODOItemPatch struct {
+	ItemTitle   *string                `json:"item_title"`
+	ItemContent *string                `json:"item_content"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	DueAt       NullableTime           `json:"due_at"`
+	RemindAt    NullableTime           `json:"remind_at"`
+	Priority    *string                `json:"priority"`
+	Tags        []string               `json:"tags"`
+}
+
+// struct used to distinguish timestamps that are explicitly
+// set to null in partial updates from timestamps that are
+// not given at all
+type NullableTime struct {
+	Set   bool
+	Value *time.Time
+}
+
+// function used to parse nullable timestamps. the timestamp is
+// marked as set for both null and non-null values
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Value = nil
+		return nil
+	}
+	var value time.Time
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.Value = &value
+	return nil
+}
+
+// struct used to filter TODO items by due date. items without
+// due dates are excluded as soon as any due filter is set
+type TODOItemFilter struct {
+	DueAfter  *time.Time
+	DueBefore *time.Time
+	Overdue   bool
+	// project used to filter items. items without a project
+	// are selected using "none". if no project is given, items
+	// in archived projects (or their subprojects) are excluded
+	Project string
+	// only return items assigned to the user
+	Assigned bool
+	// tags items must have and metadata values items must match.
+	// tags are matched against the tags owned by the user
+	Tags     []string
+	Metadata map[string]interface{}
+	// completion state used to filter items. both open
+	// and completed items are returned if not set
+	Completed *bool
+	// text query matched against item titles and contents
+	Query string
+	// field used to sort items (created, due, priority or
+	// position) and the cursor and size of the page returned.
+	// all items are returned if no limit is set
+	Sort       string
+	Descending bool
+	Cursor     string
+	Limit      int
+}
+
+// struct used to return a single page of TODO items along with
+// the total number of matching items and the cursor of the next
+// page. the cursor is nil on the last page
+type TODOItemPage struct {
+	Items      []TODOItem `json:"items"`
+	Total      int64      `json:"total"`
+	NextCursor *string    `json:"next_cursor"`
+}
+
+type Project struct {
+	ProjectId          uuid.UUID  `json:"project_id"`
+	ProjectName        string     `json:"project_name" binding:"required"`
+	ProjectDescription string     `json:"project_description"`
+	ParentId           *uuid.UUID `json:"parent_id"`
+	Archived           bool       `json:"archived"`
+	Created            time.Time  `json:"created"`
+	Items              int64      `json:"items"`
+	OpenItems          int64      `json:"open_items"`
+	Owner              string     `json:"owner"`
+	Role               string     `json:"role"`
+	UpdatedBy          *string    `json:"updated_by"`
+	UpdatedAt          *time.Time `json:"updated_at"`
+}
+
+// struct used to define the workflow of a project. items in
+// projects with a workflow are always in one of the workflow states
+// and can only be moved between states along the given transitions
+type Workflow struct {
+	ProjectId   uuid.UUID            `json:"project_id"`
+	States      []WorkflowState      `json:"states" binding:"required"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// struct used to define a single workflow state. items in done
+// states are completed. the first state of a workflow is the
+// initial state of new items, the first done state the state of
+// completed items. WIP limits of zero are treated as unlimited
+type WorkflowState struct {
+	Name     string `json:"name" binding:"required"`
+	WIPLimit int64  `json:"wip_limit"`
+	Done     bool   `json:"done"`
+	Position int64  `json:"position"`
+}
+
+type WorkflowTransition struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// struct used to store events recorded for TODO
+// items whenever their workflow state is changed
+type TodoStateChange struct {
+	EventId   uuid.UUID `json:"event_id"`
+	ItemId    uuid.UUID `json:"item_id"`
+	FromState string    `json:"from_state"`
+	ToState   string    `json:"to_state"`
+	Changed   time.Time `json:"changed"`
+	ChangedBy string    `json:"changed_by"`
+}
+
+// struct used to store time spent on TODO items. entries are either
+// logged manually or tracked using timers. running timers have no stop
+// time and their duration is evaluated up to the current time
+type TimeEntry struct {
+	EntryId     uuid.UUID  `json:"entry_id"`
+	ItemId      uuid.UUID  `json:"item_id"`
+	ItemTitle   string     `json:"item_title"`
+	ProjectId   *uuid.UUID `json:"project_id"`
+	ProjectName *string    `json:"project_name"`
+	Tags        []string   `json:"tags"`
+	Started     time.Time  `json:"started" binding:"required"`
+	Stopped     *time.Time `json:"stopped"`
+	Duration    int64      `json:"duration"`
+	Note        string     `json:"note"`
+	Manual      bool       `json:"manual"`
+}
+
+// struct used to filter time entries by item and by the
+// window in which the time entries have been started
+type TimeEntryFilter struct {
+	ItemId *uuid.UUID
+	From   *time.Time
+	To     *time.Time
+}
+
+type Tag struct {
+	TagId   uuid.UUID `json:"tag_id"`
+	TagName string    `json:"tag_name" binding:"required"`
+	Colour  string    `json:"colour"`
+	Created time.Time `json:"created"`
+	Items   int64     `json:"items"`
+}
+
+type Attachment struct {
+	AttachmentId uuid.UUID `json:"attachment_id"`
+	ItemId       uuid.UUID `json:"item_id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Digest       string    `json:"digest"`
+	Uploaded     time.Time `json:"uploaded"`
+	UploadedBy   string    `json:"uploaded_by"`
+}
+
+type Collaborator struct {
+	Uid       string    `json:"uid"`
+	Role      string    `json:"role" binding:"required"`
+	ProjectId uuid.UUID `json:"project_id"`
+	Shared    time.Time `json:"shared"`
+	SharedBy  string    `json:"shared_by"`
+}
+
+type TODOReminder struct {
+	Uid  string
+	Item TODOItem
+}
+
+// struct used to define recurring TODO items. each occurrence of a
+// series is created as a TODO item with the title, content, metadata
+// and priority of the series. only a single occurrence is open at
+// any time; the next occurrence is created once it is completed
+type TodoSeries struct {
+	SeriesId    uuid.UUID              `json:"series_id"`
+	ItemTitle   string                 `json:"item_title" binding:"required"`
+	ItemContent string                 `json:"item_content"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	Priority    string                 `json:"priority"`
+	// frequency of series (daily, weekly or monthly). daily and
+	// monthly series repeat every interval days or months, weekly
+	// series repeat on the days given in the cycle (e.g. "mon,thu")
+	Frequency string `json:"frequency" binding:"required"`
+	Interval  int64  `json:"interval"`
+	Cycle     string `json:"cycle"`
+	// due date of the first occurrence. the time of day and the day
+	// of month of monthly series are taken from the start date
+	StartAt      time.Time `json:"start_at" binding:"required"`
+	SkippedDates []string  `json:"skipped_dates"`
+	Owner        string    `json:"owner"`
+	Created      time.Time `json:"created"`
+
+	OpenItemId *uuid.UUID `json:"open_item_id"`
+	NextDue    *time.Time `json:"next_due"`
+}
+
+// define properties returned for TODO items. properties
+// are converted into items using parseTodoItem
+const todoItemProjection = `t.item_id, t.item_title, t.item_content, t.completed,
+        t.created, t.metadata, t.due_at, t.remind_at, coalesce(t.priority, 'medium'),
+        t.completed_at, [(t)-[:SUBTASK_OF]->(parent:TODO) | parent.item_id],
+        [(blocker:TODO)-[:BLOCKS]->(t) WHERE NOT blocker.completed | blocker.item_id],
+        size([(t)<-[:SUBTASK_OF*]-(child:TODO) | child]),
+        size([(t)<-[:SUBTASK_OF*]-(child:TODO) WHERE child.completed | child]),
+        [(t)-[:IN_PROJECT]->(project:Project) | project.project_id],
+        [(t)-[membership:IN_PROJECT]->(:Project) | membership.position],
+        coalesce(t.created_by, head([(owner:User)-[:OWNS]->(t) | owner.uid])),
+        t.updated_by, t.updated_at, t.completed_by,
+        [(t)-[:ASSIGNED_TO]->(assignee:User) | assignee.uid],
+        [(t)-[:OCCURRENCE_OF]->(series:TodoSeries) | series.series_id],
+        [k IN keys(t) WHERE k STARTS WITH '` + metadataPrefix + `' | [substring(k, size('` + metadataPrefix + `')), t[k]]],
+        [(t)-[:TAGGED]->(tag:Tag) | tag.tag_name],
+        head([(t)-[:IN_PROJECT]->(workflow:Project) WHERE workflow.initial_state IS NOT NULL | ` + todoItemState + `])`
+
+// define expression used to evaluate the workflow state of a TODO item
+// (t) in a project with a workflow (workflow). items that have not been
+// moved into a state of the workflow are in the initial state of the
+// workflow, or in its done state if they have been completed
+const todoItemState = `coalesce(
+        head([(t)-[:IN_STATE]->(wstate:WorkflowState)-[:STATE_OF]->(workflow) | wstate.name]),
+        CASE WHEN t.completed THEN workflow.done_state ELSE workflow.initial_state END)`
+
+// define expressions used to evaluate the role of a user (u) for a
+// TODO item (t) or project (p). items are accessible to their owner
+// and to the owner and collaborators of the (parent) projects they
+// are part of. the expressions evaluate to null if there is no access
+const todoItemRole = `CASE
+        WHEN exists((u)-[:OWNS]->(t))
+        OR exists((u)-[:OWNS]->(:Project)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(t)) THEN 'owner'
+        WHEN exists((u)-[:COLLABORATES_ON {role: 'editor'}]->(:Project)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(t)) THEN 'editor'
+        WHEN exists((u)-[:COLLABORATES_ON]->(:Project)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(t)) THEN 'viewer'
+        ELSE null END`
+
+const projectRole = `CASE
+        WHEN exists((u)-[:OWNS]->(:Project)<-[:SUBPROJECT_OF*0..]-(p)) THEN 'owner'
+        WHEN exists((u)-[:COLLABORATES_ON {role: 'editor'}]->(:Project)<-[:SUBPROJECT_OF*0..]-(p)) THEN 'editor'
+        WHEN exists((u)-[:COLLABORATES_ON]->(:Project)<-[:SUBPROJECT_OF*0..]-(p)) THEN 'viewer'
+        ELSE null END`
+
+// function used to convert a list of values returned
+// using the TODO item projection into a TODO item
+func parseTodoItem(values []interface{}) TODOItem {
+	itemId, _ := uuid.Parse(values[0].(string))
+	item := TODOItem{
+		ItemId:      itemId,
+		ItemTitle:   values[1].(string),
+		ItemContent: values[2].(string),
+		Completed:   values[3].(bool),
+		Created:     values[4].(time.Time),
+		Metadata:    parseTodoMetadata(values[5], values[22]),
+		DueAt:       parseOptionalTime(values[6]),
+		RemindAt:    parseOptionalTime(values[7]),
+		Priority:    values[8].(string),
+		CompletedAt: parseOptionalTime(values[9]),
+
+		BlockedBy:         parseIdList(values[11]),
+		Subtasks:          values[12].(int64),
+		CompletedSubtasks: values[13].(int64),
+	}
+	if parents := parseIdList(values[10]); len(parents) > 0 {
+		item.ParentId = &parents[0]
+	}
+	if projects := parseIdList(values[14]); len(projects) > 0 {
+		item.ProjectId = &projects[0]
+	}
+	if positions := values[15].([]interface{}); len(positions) > 0 {
+		position := positions[0].(int64)
+		item.Position = &position
+	}
+	item.CreatedBy, _ = values[16].(string)
+	item.UpdatedBy = parseOptionalString(values[17])
+	item.UpdatedAt = parseOptionalTime(values[18])
+	item.CompletedBy = parseOptionalString(values[19])
+	if assignees := values[20].([]interface{}); len(assignees) > 0 {
+		item.AssignedTo = parseOptionalString(assignees[0])
+	}
+	if series := parseIdList(values[21]); len(series) > 0 {
+		item.SeriesId = &series[0]
+	}
+	item.State = parseOptionalString(values[24])
+	// tags are shared between collaborators, so that the
+	// same tag name can be attached by multiple users
+	item.Tags = []string{}
+	for _, tag := range values[23].([]interface{}) {
+		if !utils.StringSliceContains(item.Tags, tag.(string)) {
+			item.Tags = append(item.Tags, tag.(string))
+		}
+	}
+	item.Overdue = isTodoItemOverdue(item, time.Now().UTC())
+	item.Blocked = len(item.BlockedBy) > 0
+	item.Progress = getTodoItemProgress(item)
+	return item
+}
+
+// function used to convert a list of item IDs returned
+// from the graph. invalid IDs are skipped
+func parseIdList(value interface{}) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, v := range value.([]interface{}) {
+		if id, err := uuid.Parse(v.(string)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// function used to convert optional graph timestamps. null
+// values are returned as nil pointers
+func parseOptionalTime(value interface{}) *time.Time {
+	if value == nil {
+		return nil
+	}
+	ts := value.(time.Time)
+	return &ts
+}
+
+// function used to convert optional graph strings. null
+// values are returned as nil pointers
+func parseOptionalString(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+	v := value.(string)
+	return &v
+}
+
+// function used to authorize a user for a todo item within a
+// transaction. users without access are treated as if the item
+// did not exist. if edit is set, users must be owners or editors
+func authorizeTodoItem(tx neo4j.Transaction, uid string, itemId uuid.UUID, edit bool) error {
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"item_id": itemId.String(),
+	}
+	query := `MATCH (u:User {uid: $uid}), (t:TODO {item_id: $item_id})
+        RETURN ` + todoItemRole
+	results, err := tx.Run(query, cfg)
+	if err != nil {
+		return err
+	}
+	node, err := neo4j.Single(results, err)
+	if err != nil || node.Values[0] == nil {
+		return ErrTODOItemNotFound
+	}
+	if edit && node.Values[0].(string) == roleViewer {
+		return ErrTODOPermissionDenied
+	}
+	return nil
+}
+
+// function used to authorize a user for a project within a
+// transaction. users must hold at least the required role
+func authorizeProject(tx neo4j.Transaction, uid string, projectId uuid.UUID, required string) error {
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+	}
+	query := `MATCH (u:User {uid: $uid}), (p:Project {project_id: $project_id})
+        RETURN ` + projectRole
+	results, err := tx.Run(query, cfg)
+	if err != nil {
+		return err
+	}
+	node, err := neo4j.Single(results, err)
+	if err != nil || node.Values[0] == nil {
+		return ErrProjectNotFound
+	}
+	if !roleAllows(node.Values[0].(string), required) {
+		return ErrTODOPermissionDenied
+	}
+	return nil
+}
+
+// function used to convert optional timestamps into graph
+// parameters. nil values are passed as nulls
+func optionalTimeParam(ts *time.Time) interface{} {
+	if ts == nil {
+		return nil
+	}
+	return ts.UTC()
+}
+
+// function used to replace the typed metadata properties of a todo
+// item within a transaction. metadata stored as a JSON string is
+// removed once the item has been converted to typed properties
+func setTodoItemMetadata(tx neo4j.Transaction, itemId string, metadata map[string]interface{}) error {
+	cfg := map[string]interface{}{
+		"item_id": itemId,
+	}
+	query := `MATCH (t:TODO {item_id: $item_id})
+        RETURN [k IN keys(t) WHERE k STARTS WITH '` + metadataPrefix + `']`
+	results, err := tx.Run(query, cfg)
+	if err != nil {
+		return err
+	}
+	node, err := neo4j.Single(results, err)
+	if err != nil {
+		return ErrTODOItemNotFound
+	}
+	// existing properties are removed by setting them to null
+	properties := metadataProperties(metadata)
+	for _, key := range node.Values[0].([]interface{}) {
+		if _, ok := properties[key.(string)]; !ok {
+			properties[key.(string)] = nil
+		}
+	}
+	properties["metadata"] = nil
+	cfg["properties"] = properties
+	_, err = tx.Run(`MATCH (t:TODO {item_id: $item_id})
+        SET t += $properties`, cfg)
+	return err
+}
+
+// function used to replace the tags of a user on a todo item within
+// a transaction. tags are matched by name and created if the user
+// does not own a tag with the given name. tags of other users are kept
+func setTodoItemTags(tx neo4j.Transaction, uid, itemId string, tags []string) error {
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"item_id": itemId,
+		"tags":    tags,
+	}
+	query := `MATCH (u:User {uid: $uid}), (t:TODO {item_id: $item_id})
+        OPTIONAL MATCH (t)-[r:TAGGED]->(:Tag)<-[:OWNS]-(u)
+        DELETE r
+        WITH DISTINCT u, t
+        UNWIND $tags AS name
+        MERGE (u)-[:OWNS]->(g:Tag {tag_name: name})
+        ON CREATE SET g.tag_id = randomUUID(), g.colour = '', g.created = datetime()
+        CREATE (t)-[:TAGGED]->(g)`
+	_, err := tx.Run(query, cfg)
+	return err
+}
+
+// function used to delete the attachments matched by a given
+// pattern (binding attachments to a) within a transaction. the
+// distinct digests of the deleted attachments are returned
+func deleteAttachments(tx neo4j.Transaction, pattern string, cfg map[string]interface{}) ([]string, error) {
+	query := pattern + `
+        WITH collect(a) AS attachments, collect(DISTINCT a.digest) AS digests
+        FOREACH (n IN attachments | DETACH DELETE n)
+        RETURN digests`
+	results, err := tx.Run(query, cfg)
+	if err != nil {
+		return nil, err
+	}
+	node, err := neo4j.Single(results, err)
+	if err != nil {
+		return nil, err
+	}
+	digests := []string{}
+	for _, digest := range node.Values[0].([]interface{}) {
+		digests = append(digests, digest.(string))
+	}
+	return digests, nil
 }
 
 // function used to create a new todo item for a given user
-func (db *GraphPersistence) CreateTodoItem(uid string, item TODOItem) error {
+func (db *GraphPersistence) CreateTodoItem(uid string, item TODOItem) (uuid.UUID, error) {
 	log.Debug(fmt.Sprintf("creating new TODO item for user %s", uid))
 	session := db.NewSession()
 	defer session.Close()
 
-	// convert metadata to JSON string and store
-	meta, err := json.Marshal(item.Metadata)
-	if err != nil {
-		log.Error(fmt.Errorf("unable to convert toto metadata: %+v", err))
-		return ErrInvalidTODOMetadata
-	}
+	itemId := uuid.New()
 	cfg := map[string]interface{}{
 		"uid":          uid,
-		"item_id":      uuid.New().String(),
+		"item_id":      itemId.String(),
 		"item_title":   item.ItemTitle,
 		"item_content": item.ItemContent,
-		"metadata":     string(meta),
+		"properties":   metadataProperties(item.Metadata),
+		"due_at":       optionalTimeParam(item.DueAt),
+		"remind_at":    optionalTimeParam(item.RemindAt),
+		"priority":     item.Priority,
 	}
 	// define handler to execute node query
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if item.ProjectId != nil {
+			if err := authorizeProject(tx, uid, *item.ProjectId, roleEditor); err != nil {
+				return nil, err
+			}
+		}
 		query := `CREATE (t:TODO {
             item_id: $item_id,
             item_title: $item_title,
             item_content: $item_content,
             created: datetime(),
             completed: false,
-            metadata: $metadata
+            due_at: $due_at,
+            remind_at: $remind_at,
+            priority: $priority,
+            created_by: $uid
         })
+        SET t += $properties
         WITH t
         MATCH (u:User {uid: $uid})
         CREATE (u)-[:OWNS]->(t)`
-		return tx.Run(query, cfg)
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		if item.ProjectId != nil {
+			// append item to end of project
+			cfg["project_id"] = item.ProjectId.String()
+			query = `MATCH (p:Project {project_id: $project_id})
+            OPTIONAL MATCH (p)<-[r:IN_PROJECT]-()
+            WITH p, coalesce(max(r.position) + 1, 0) AS position
+            MATCH (t:TODO {item_id: $item_id})
+            CREATE (t)-[:IN_PROJECT {position: position}]->(p)`
+			if _, err := tx.Run(query, cfg); err != nil {
+				return nil, err
+			}
+		}
+		return nil, setTodoItemTags(tx, uid, cfg["item_id"].(string), item.Tags)
 	}
 	// execute handler within write transaction
-	_, err = session.WriteTransaction(handler)
+	_, err := session.WriteTransaction(handler)
 	if err != nil {
 		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
-		return err
+		return itemId, err
 	}
-	return nil
+	return itemId, nil
 }
 
-// function used to retrieve todo items for a given user
+// function used to retrieve a todo item with given item ID
 func (db *GraphPersistence) GetTodoItem(uid string, itemId uuid.UUID) (TODOItem, error) {
 	log.Debug(fmt.Sprintf("retrieving TODO item %s for user %s", itemId, uid))
 	session := db.NewSession()
@@ -100,9 +666,11 @@ func (db *GraphPersistence) GetTodoItem(uid string, itemId uuid.UUID) (TODOItem,
 		"item_id": itemId.String(),
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
-		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(t:TODO {item_id: $item_id})
-        RETURN t.item_id, t.item_title, t.item_content, t.completed, t.created, t.metadata
-        `
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})
+        RETURN ` + todoItemProjection
 		// run graph query to fetch TODO item
 		results, err := tx.Run(query, cfg)
 		if err != nil {
@@ -121,71 +689,240 @@ func (db *GraphPersistence) GetTodoItem(uid string, itemId uuid.UUID) (TODOItem,
 	if err != nil {
 		return TODOItem{}, err
 	}
+	return parseTodoItem(node.Values), nil
+}
 
-	var metadata map[string]interface{}
-	// convert metadata from JSON string to struct
-	json.Unmarshal([]byte(node.Values[5].(string)), &metadata)
-	return TODOItem{
-		ItemId:      node.Values[0].(uuid.UUID),
-		ItemTitle:   node.Values[1].(string),
-		ItemContent: node.Values[2].(string),
-		Completed:   node.Values[3].(bool),
-		Created:     node.Values[4].(time.Time),
-		Metadata:    metadata,
-	}, nil
+// function used to update the title, content, metadata, due dates,
+// priority and tags of a todo item. changing the reminder time of an
+// item resets its reminder so that it is delivered again
+func (db *GraphPersistence) UpdateTodoItem(uid string, itemId uuid.UUID, item TODOItem) error {
+	log.Debug(fmt.Sprintf("updating TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":          uid,
+		"item_id":      itemId.String(),
+		"item_title":   item.ItemTitle,
+		"item_content": item.ItemContent,
+		"due_at":       optionalTimeParam(item.DueAt),
+		"remind_at":    optionalTimeParam(item.RemindAt),
+		"priority":     item.Priority,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})
+        SET t.reminded_at = CASE WHEN t.remind_at = $remind_at THEN t.reminded_at ELSE null END
+        SET t.item_title = $item_title, t.item_content = $item_content,
+        t.due_at = $due_at, t.remind_at = $remind_at, t.priority = $priority,
+        t.updated_by = $uid, t.updated_at = datetime()`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		if err := setTodoItemMetadata(tx, itemId.String(), item.Metadata); err != nil {
+			return nil, err
+		}
+		if item.Tags == nil {
+			return nil, nil
+		}
+		return nil, setTodoItemTags(tx, uid, itemId.String(), item.Tags)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return err
+	}
+	return nil
 }
 
-// function used to retrieve a todo item with given todo ID
-func (db *GraphPersistence) GetTodoItems(uid string) ([]TODOItem, error) {
+// function used to retrieve todo items for a given user, including
+// items in projects shared with the user. items can optionally be
+// filtered by due window, overdue status, project, assignment,
+// completion state, tags, metadata and text query, and are
+// returned in pages
+func (db *GraphPersistence) GetTodoItems(uid string, filter TODOItemFilter) (TODOItemPage, error) {
 	log.Debug(fmt.Sprintf("retreiving TODO item(s) for user %s", uid))
 	session := db.NewSession()
 	defer session.Close()
 
-	items := []TODOItem{}
+	// items in a single project are sorted by their position in
+	// the project by default, all other items by their due date
+	page := TODOItemPage{Items: []TODOItem{}}
+	projectSelected := filter.Project != "" && filter.Project != "none"
+	if len(filter.Sort) == 0 && projectSelected {
+		filter.Sort = "position"
+	} else if len(filter.Sort) == 0 {
+		filter.Sort = "due"
+	}
+	sortKey, ok := todoSortKeys[filter.Sort]
+	if !ok || (filter.Sort == "position" && !projectSelected) {
+		return page, ErrInvalidTODOSort
+	}
 	cfg := map[string]interface{}{
-		"uid": uid,
+		"uid":        uid,
+		"due_after":  optionalTimeParam(filter.DueAfter),
+		"due_before": optionalTimeParam(filter.DueBefore),
+		"overdue":    filter.Overdue,
+		"now":        time.Now().UTC(),
+		"project":    filter.Project,
+		"assigned":   filter.Assigned,
+		"completed":  nil,
+		"tags":       filter.Tags,
+		"metadata":   filter.Metadata,
+		"query":      nil,
+		"descending": filter.Descending,
+		"cursor_key": nil,
+		"cursor_id":  nil,
+		"limit":      filter.Limit + 1,
+
+		"search_limit": maxTodoSearchResults,
+	}
+	if filter.Completed != nil {
+		cfg["completed"] = *filter.Completed
 	}
+	if len(filter.Query) > 0 {
+		query, err := normalizeSearchQuery(filter.Query)
+		if err != nil {
+			return page, err
+		}
+		cfg["query"] = query
+	}
+	if len(filter.Cursor) > 0 {
+		key, itemId, err := decodeTodoItemCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return page, err
+		}
+		cfg["cursor_key"], cfg["cursor_id"] = key, itemId.String()
+	}
+
+	// all items accessible to the user are considered. if a text
+	// query is given, hits from the full-text index are checked for
+	// access as they are read, and the search stops once the most
+	// relevant accessible hits have been found
+	query := `MATCH (u:User {uid: $uid})
+        OPTIONAL MATCH (u)-[:OWNS|COLLABORATES_ON]->(:Project)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(shared:TODO)
+        WITH u, collect(DISTINCT shared) + [(u)-[:OWNS]->(own:TODO) | own] AS accessible
+        UNWIND accessible AS t`
+	if len(filter.Query) > 0 {
+		query = `MATCH (u:User {uid: $uid})
+        CALL db.index.fulltext.queryNodes('todo_search', $query) YIELD node AS t
+        WHERE exists((u)-[:OWNS]->(t))
+        OR exists((u)-[:OWNS|COLLABORATES_ON]->(:Project)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(t))
+        WITH u, t LIMIT $search_limit`
+	}
+	query += `
+        WITH DISTINCT u, t
+        WHERE ($due_after IS NULL OR t.due_at >= $due_after)
+        AND ($due_before IS NULL OR t.due_at < $due_before)
+        AND (NOT $overdue OR (NOT t.completed AND t.due_at < $now))
+        AND (($project = '' AND NOT exists((t)-[:IN_PROJECT]->(:Project)-[:SUBPROJECT_OF*0..]->(:Project {archived: true})))
+            OR ($project = 'none' AND NOT exists((t)-[:IN_PROJECT]->(:Project)))
+            OR exists((t)-[:IN_PROJECT]->(:Project {project_id: $project})))
+        AND (NOT $assigned OR exists((t)-[:ASSIGNED_TO]->(u)))
+        AND ($completed IS NULL OR t.completed = $completed)
+        AND all(name IN coalesce($tags, []) WHERE exists((t)-[:TAGGED]->(:Tag {tag_name: name})<-[:OWNS]-(u)))
+        AND all(k IN keys(coalesce($metadata, {})) WHERE t['` + metadataPrefix + `' + k] = $metadata[k])`
+
+	// items after the cursor are selected in sort order, using
+	// the item ID to order items with equal sort keys
+	comparison := ">"
+	direction := "ASC"
+	if filter.Descending {
+		comparison, direction = "<", "DESC"
+	}
+	pageQuery := query + `
+        WITH u, t, ` + sortKey + ` AS sort_key
+        WHERE $cursor_id IS NULL OR sort_key ` + comparison + ` $cursor_key
+        OR (sort_key = $cursor_key AND t.item_id ` + comparison + ` $cursor_id)
+        RETURN ` + todoItemProjection + `, sort_key
+        ORDER BY sort_key ` + direction + `, t.item_id ` + direction
+	if filter.Limit > 0 {
+		pageQuery += `
+        LIMIT $limit`
+	}
+
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
-		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(t:TODO)
-        RETURN t.item_id, t.item_title, t.item_content, t.completed,
-        t.created, t.metadata`
-		return neo4j.Collect(tx.Run(query, cfg))
+		results, err := tx.Run(query+`
+        RETURN count(t)`, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = node.Values[0].(int64)
+		return neo4j.Collect(tx.Run(pageQuery, cfg))
 	}
 	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
 	if err != nil {
 		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
-		return items, err
+		return page, err
 	}
-	for _, node := range nodes {
-		itemId, _ := uuid.Parse(node.Values[0].(string))
-		var metadata map[string]interface{}
-		// convert metadata from JSON string to struct
-		json.Unmarshal([]byte(node.Values[5].(string)), &metadata)
-		items = append(items, TODOItem{
-			ItemId:      itemId,
-			ItemTitle:   node.Values[1].(string),
-			ItemContent: node.Values[2].(string),
-			Completed:   node.Values[3].(bool),
-			Created:     node.Values[4].(time.Time),
-			Metadata:    metadata,
-		})
+	for i, node := range nodes {
+		if filter.Limit > 0 && i == filter.Limit {
+			// additional item was retrieved; return cursor
+			// pointing to the last item of the page
+			last := nodes[i-1]
+			cursor := encodeTodoItemCursor(last.Values[len(last.Values)-1], page.Items[i-1].ItemId)
+			page.NextCursor = &cursor
+			break
+		}
+		page.Items = append(page.Items, parseTodoItem(node.Values))
 	}
-	return items, nil
+	return page, nil
 }
 
-// function used to complete a given todo item
+// function used to complete a given todo item. the time of
+// completion is kept if the item has already been completed
 func (db *GraphPersistence) CompleteTodoItem(uid string, itemId uuid.UUID) error {
 	log.Debug(fmt.Sprintf("completing TODO item %s for user %s", itemId, uid))
+	return db.setTodoItemCompleted(uid, itemId, true)
+}
+
+// function used to reopen a given (completed) todo item
+func (db *GraphPersistence) ReopenTodoItem(uid string, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("reopening TODO item %s for user %s", itemId, uid))
+	return db.setTodoItemCompleted(uid, itemId, false)
+}
+
+// function used to set the completion state of a todo item
+func (db *GraphPersistence) setTodoItemCompleted(uid string, itemId uuid.UUID, completed bool) error {
 	session := db.NewSession()
 	defer session.Close()
 
 	cfg := map[string]interface{}{
-		"uid":     uid,
-		"item_id": itemId.String(),
+		"uid":       uid,
+		"item_id":   itemId.String(),
+		"completed": completed,
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
-		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(t:TODO {item_id: $item_id})
-        SET t.completed = true`
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		// items in projects with a workflow are moved into the done state
+		// (or initial state when reopened) of the workflow if the state of
+		// the item does not already match the completion state
+		query := `MATCH (t:TODO {item_id: $item_id})-[:IN_PROJECT]->(workflow:Project)<-[:STATE_OF]-(target:WorkflowState)
+        WHERE target.name = CASE WHEN $completed THEN workflow.done_state ELSE workflow.initial_state END
+        OPTIONAL MATCH (t)-[r:IN_STATE]->(current:WorkflowState)-[:STATE_OF]->(workflow)
+        WITH t, target, r, coalesce(current.done, t.completed) AS done, ` + todoItemState + ` AS previous
+        WHERE done <> $completed
+        DELETE r
+        CREATE (t)-[:IN_STATE]->(target)
+        CREATE (t)-[:HAS_EVENT]->(:StateChange {event_id: randomUUID(), from_state: previous,
+            to_state: target.name, changed: datetime(), changed_by: $uid})`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		query = `MATCH (t:TODO {item_id: $item_id})
+        SET t.completed_by = CASE WHEN $completed
+        THEN coalesce(t.completed_by, $uid) ELSE null END,
+        t.completed_at = CASE WHEN $completed
+        THEN coalesce(t.completed_at, datetime()) ELSE null END,
+        t.completed = $completed, t.updated_by = $uid, t.updated_at = datetime()`
 		return tx.Run(query, cfg)
 	}
 	_, err := session.WriteTransaction(handler)
@@ -196,8 +933,55 @@ func (db *GraphPersistence) CompleteTodoItem(uid string, itemId uuid.UUID) error
 	return nil
 }
 
-// function used to create a new todo item for a given user
-func (db *GraphPersistence) DeleteTodoItem(uid string, itemId uuid.UUID) error {
+// function used to mark a todo item as promoted into a habit (or
+// to release the promotion if the habit could not be created). items
+// can only be promoted once. the item is locked before the flag is
+// checked so that concurrent requests cannot both promote the item
+func (db *GraphPersistence) SetTodoItemPromoted(uid string, itemId uuid.UUID, promoted bool) error {
+	log.Debug(fmt.Sprintf("setting promotion of TODO item %s for user %s to %t", itemId, uid, promoted))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"item_id":  itemId.String(),
+		"promoted": promoted,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})
+        SET t.updated_by = $uid, t.updated_at = datetime()
+        RETURN coalesce(t.promoted, false)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if promoted && node.Values[0].(bool) {
+			return nil, ErrTODOItemPromoted
+		}
+		query = `MATCH (t:TODO {item_id: $item_id})
+        SET t.promoted = $promoted,
+        t.promoted_at = CASE WHEN $promoted THEN datetime() ELSE null END`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to set TODO item promotion: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to delete a todo item along with its attachments.
+// the digests of the deleted attachments are returned so that
+// unreferenced blobs can be removed from the blob store
+func (db *GraphPersistence) DeleteTodoItem(uid string, itemId uuid.UUID) ([]string, error) {
 	log.Debug(fmt.Sprintf("deleting TODO item %s for user %s", itemId, uid))
 	session := db.NewSession()
 	defer session.Close()
@@ -207,14 +991,2150 @@ func (db *GraphPersistence) DeleteTodoItem(uid string, itemId uuid.UUID) error {
 		"item_id": itemId.String(),
 	}
 	handler := func(tx neo4j.Transaction) (interface{}, error) {
-		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(t:TODO {item_id: $item_id})
-        DETACH DELETE t`
-		return tx.Run(query, cfg)
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		digests, err := deleteAttachments(tx, `MATCH (:TODO {item_id: $item_id})<-[:ATTACHED_TO]-(a:Attachment)`, cfg)
+		if err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})
+        OPTIONAL MATCH (t)-[:HAS_EVENT]->(e:StateChange)
+        OPTIONAL MATCH (t)<-[:SPENT_ON]-(te:TimeEntry)
+        DETACH DELETE e, te, t`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		return digests, nil
 	}
-	_, err := session.WriteTransaction(handler)
+	digests, err := session.WriteTransaction(handler)
 	if err != nil {
 		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
-		return err
+		return nil, err
+	}
+	return digests.([]string), nil
+}
+
+// function used to retrieve all open TODO items whose reminder
+// time has passed and for which no reminder has been sent yet.
+// reminders are sent to the assignee of an item, or to its owner
+// if the item has not been assigned
+func (db *GraphPersistence) GetPendingTodoReminders(now time.Time) ([]TODOReminder, error) {
+	log.Debug("retrieving pending TODO reminders")
+	session := db.NewSession()
+	defer session.Close()
+
+	reminders := []TODOReminder{}
+	cfg := map[string]interface{}{
+		"now": now,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User)-[:OWNS]->(t:TODO)
+        WHERE t.remind_at <= $now AND NOT t.completed AND t.reminded_at IS NULL
+        RETURN coalesce(head([(t)-[:ASSIGNED_TO]->(a:User) | a.uid]), u.uid), ` + todoItemProjection
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return reminders, err
+	}
+	for _, node := range nodes {
+		reminders = append(reminders, TODOReminder{
+			Uid:  node.Values[0].(string),
+			Item: parseTodoItem(node.Values[1:]),
+		})
+	}
+	return reminders, nil
+}
+
+// function used to mark the reminder of a TODO item as sent
+func (db *GraphPersistence) MarkTodoReminderSent(itemId uuid.UUID, sent time.Time) error {
+	log.Debug(fmt.Sprintf("marking reminder for TODO item %s as sent", itemId))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"item_id": itemId.String(),
+		"sent":    sent,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (t:TODO {item_id: $item_id})
+        SET t.reminded_at = $sent`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to create a dependency between two todo items. both
+// subtask and blocking relationships point from the item that has to
+// be completed first to the dependent item, so that cycles can be
+// detected over both relationship types at once
+func (db *GraphPersistence) addTodoDependency(uid string, fromId, toId uuid.UUID, relationship string) error {
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"from_id": fromId.String(),
+		"to_id":   toId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		// check that both items can be edited and that the dependent
+		// item is not (indirectly) required by the item it depends on
+		for _, id := range []uuid.UUID{fromId, toId} {
+			if err := authorizeTodoItem(tx, uid, id, true); err != nil {
+				return nil, err
+			}
+		}
+		query := `MATCH (f:TODO {item_id: $from_id}), (t:TODO {item_id: $to_id})
+        RETURN exists((t)-[:BLOCKS|SUBTASK_OF*0..]->(f))`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrTODOItemNotFound
+		}
+		if node.Values[0].(bool) {
+			return nil, ErrTODODependencyCycle
+		}
+
+		// items can only be subtasks of a single parent
+		if relationship == "SUBTASK_OF" {
+			query = `MATCH (f:TODO {item_id: $from_id})-[r:SUBTASK_OF]->()
+            DELETE r`
+			if _, err := tx.Run(query, cfg); err != nil {
+				return nil, err
+			}
+		}
+		query = fmt.Sprintf(`MATCH (f:TODO {item_id: $from_id}), (t:TODO {item_id: $to_id})
+        MERGE (f)-[:%s]->(t)
+        SET t.updated_by = $uid, t.updated_at = datetime()`, relationship)
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create TODO dependency: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to remove a dependency between two todo items
+func (db *GraphPersistence) removeTodoDependency(uid string, fromId, toId uuid.UUID, relationship string) error {
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"from_id": fromId.String(),
+		"to_id":   toId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		for _, id := range []uuid.UUID{fromId, toId} {
+			if err := authorizeTodoItem(tx, uid, id, true); err != nil {
+				return nil, err
+			}
+		}
+		query := fmt.Sprintf(`MATCH (:TODO {item_id: $from_id})-[r:%s]->(t:TODO {item_id: $to_id})
+        DELETE r
+        SET t.updated_by = $uid, t.updated_at = datetime()
+        RETURN COUNT(r)`, relationship)
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrTODODependencyNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to remove TODO dependency: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to make a todo item a subtask of a parent item.
+// items that are already subtasks are moved to the new parent
+func (db *GraphPersistence) AddTodoSubtask(uid string, parentId, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("adding TODO item %s as subtask of %s for user %s", itemId, parentId, uid))
+	return db.addTodoDependency(uid, itemId, parentId, "SUBTASK_OF")
+}
+
+// function used to detach a subtask from its parent item
+func (db *GraphPersistence) RemoveTodoSubtask(uid string, parentId, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("removing TODO item %s as subtask of %s for user %s", itemId, parentId, uid))
+	return db.removeTodoDependency(uid, itemId, parentId, "SUBTASK_OF")
+}
+
+// function used to mark a todo item as blocked by another item
+func (db *GraphPersistence) AddTodoBlocker(uid string, blockerId, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("adding TODO item %s as blocker of %s for user %s", blockerId, itemId, uid))
+	return db.addTodoDependency(uid, blockerId, itemId, "BLOCKS")
+}
+
+// function used to remove a blocker from a todo item
+func (db *GraphPersistence) RemoveTodoBlocker(uid string, blockerId, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("removing TODO item %s as blocker of %s for user %s", blockerId, itemId, uid))
+	return db.removeTodoDependency(uid, blockerId, itemId, "BLOCKS")
+}
+
+// function used to convert optional item IDs into graph
+// parameters. nil values are passed as nulls
+func optionalIdParam(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// function used to create a new project for a given user. projects
+// can be nested within an existing parent project
+func (db *GraphPersistence) CreateProject(uid string, project Project) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating new project for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	projectId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":                 uid,
+		"project_id":          projectId.String(),
+		"project_name":        project.ProjectName,
+		"project_description": project.ProjectDescription,
+		"parent_id":           optionalIdParam(project.ParentId),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        OPTIONAL MATCH (u)-[:OWNS]->(parent:Project {project_id: $parent_id})
+        WITH u, parent WHERE $parent_id IS NULL OR parent IS NOT NULL
+        CREATE (u)-[:OWNS]->(p:Project {
+            project_id: $project_id,
+            project_name: $project_name,
+            project_description: $project_description,
+            archived: false,
+            created: datetime()
+        })
+        FOREACH (x IN CASE WHEN parent IS NULL THEN [] ELSE [parent] END |
+            CREATE (p)-[:SUBPROJECT_OF]->(x))
+        RETURN p.project_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrProjectNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create project: %+v", err))
+		return uuid.Nil, err
+	}
+	return projectId, nil
+}
+
+// function used to retrieve the projects of a given user,
+// including projects shared with the user
+func (db *GraphPersistence) GetProjects(uid string, includeArchived bool) ([]Project, error) {
+	log.Debug(fmt.Sprintf("retrieving projects for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	projects := []Project{}
+	cfg := map[string]interface{}{
+		"uid":              uid,
+		"include_archived": includeArchived,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        OPTIONAL MATCH (u)-[:COLLABORATES_ON]->(:Project)<-[:SUBPROJECT_OF*0..]-(shared:Project)
+        WITH u, collect(DISTINCT shared) + [(u)-[:OWNS]->(own:Project) | own] AS accessible
+        UNWIND accessible AS p
+        WITH DISTINCT u, p
+        WHERE $include_archived OR NOT p.archived
+        RETURN p.project_id, p.project_name, p.project_description, p.archived, p.created,
+        [(p)-[:SUBPROJECT_OF]->(x:Project) | x.project_id],
+        size([(p)<-[:IN_PROJECT]-(t:TODO) | t]),
+        size([(p)<-[:IN_PROJECT]-(t:TODO) WHERE NOT t.completed | t]),
+        head([(o:User)-[:OWNS]->(p) | o.uid]), ` + projectRole + `,
+        p.updated_by, p.updated_at
+        ORDER BY p.project_name`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return projects, err
+	}
+	for _, node := range nodes {
+		projectId, _ := uuid.Parse(node.Values[0].(string))
+		project := Project{
+			ProjectId:          projectId,
+			ProjectName:        node.Values[1].(string),
+			ProjectDescription: node.Values[2].(string),
+			Archived:           node.Values[3].(bool),
+			Created:            node.Values[4].(time.Time),
+			Items:              node.Values[6].(int64),
+			OpenItems:          node.Values[7].(int64),
+			Owner:              node.Values[8].(string),
+			Role:               node.Values[9].(string),
+			UpdatedBy:          parseOptionalString(node.Values[10]),
+			UpdatedAt:          parseOptionalTime(node.Values[11]),
+		}
+		if parents := parseIdList(node.Values[5]); len(parents) > 0 {
+			project.ParentId = &parents[0]
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// function used to update the name, description and parent of a
+// project. projects cannot be nested within their own subprojects
+func (db *GraphPersistence) UpdateProject(uid string, projectId uuid.UUID, project Project) error {
+	log.Debug(fmt.Sprintf("updating project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":                 uid,
+		"project_id":          projectId.String(),
+		"project_name":        project.ProjectName,
+		"project_description": project.ProjectDescription,
+		"parent_id":           optionalIdParam(project.ParentId),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(p:Project {project_id: $project_id})
+        OPTIONAL MATCH (u)-[:OWNS]->(parent:Project {project_id: $parent_id})
+        RETURN parent IS NOT NULL, parent IS NOT NULL AND exists((parent)-[:SUBPROJECT_OF*0..]->(p))`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrProjectNotFound
+		}
+		if project.ParentId != nil && !node.Values[0].(bool) {
+			return nil, ErrProjectNotFound
+		}
+		if node.Values[1].(bool) {
+			return nil, ErrProjectCycle
+		}
+
+		query = `MATCH (p:Project {project_id: $project_id})
+        SET p.project_name = $project_name, p.project_description = $project_description,
+        p.updated_by = $uid, p.updated_at = datetime()
+        WITH p
+        OPTIONAL MATCH (p)-[r:SUBPROJECT_OF]->()
+        DELETE r
+        WITH DISTINCT p
+        MATCH (parent:Project {project_id: $parent_id})
+        CREATE (p)-[:SUBPROJECT_OF]->(parent)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update project: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to archive or unarchive a project
+func (db *GraphPersistence) SetProjectArchived(uid string, projectId uuid.UUID, archived bool) error {
+	log.Debug(fmt.Sprintf("setting archived state of project %s for user %s to %t", projectId, uid, archived))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+		"archived":   archived,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(p:Project {project_id: $project_id})
+        SET p.archived = $archived, p.updated_by = $uid, p.updated_at = datetime()
+        RETURN p.project_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrProjectNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to set project archived state: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to move a todo item into a project. items are
+// removed from their current project and placed at the given
+// position, or at the end of the project if no position is given
+func (db *GraphPersistence) MoveTodoItemToProject(uid string, projectId, itemId uuid.UUID, position *int64) error {
+	log.Debug(fmt.Sprintf("moving TODO item %s to project %s for user %s", itemId, projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+		"item_id":    itemId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleEditor); err != nil {
+			return nil, err
+		}
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+
+		query := `MATCH (t:TODO {item_id: $item_id})
+        SET t.updated_by = $uid, t.updated_at = datetime()
+        WITH t
+        MATCH (t)-[r:IN_PROJECT|IN_STATE]->()
+        DELETE r`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		if position == nil {
+			// append item to end of project
+			query = `MATCH (p:Project {project_id: $project_id})
+            OPTIONAL MATCH (p)<-[r:IN_PROJECT]-()
+            WITH p, coalesce(max(r.position) + 1, 0) AS position
+            MATCH (t:TODO {item_id: $item_id})
+            CREATE (t)-[:IN_PROJECT {position: position}]->(p)`
+		} else {
+			// shift items at or after position to make space
+			cfg["position"] = *position
+			query = `MATCH (p:Project {project_id: $project_id})
+            OPTIONAL MATCH (p)<-[r:IN_PROJECT]-()
+            WHERE r.position >= $position
+            SET r.position = r.position + 1
+            WITH DISTINCT p
+            MATCH (t:TODO {item_id: $item_id})
+            CREATE (t)-[:IN_PROJECT {position: $position}]->(p)`
+		}
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to move TODO item to project: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to remove a todo item from a project
+func (db *GraphPersistence) RemoveTodoItemFromProject(uid string, projectId, itemId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("removing TODO item %s from project %s for user %s", itemId, projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+		"item_id":    itemId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleEditor); err != nil {
+			return nil, err
+		}
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})-[r:IN_PROJECT]->(:Project {project_id: $project_id})
+        OPTIONAL MATCH (t)-[s:IN_STATE]->()
+        DELETE r, s
+        SET t.updated_by = $uid, t.updated_at = datetime()
+        RETURN COUNT(DISTINCT r)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrProjectItemNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to remove TODO item from project: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to reorder the items within a project. items are
+// placed in the given order, followed by any items that are part
+// of the project but not listed (in their previous order)
+func (db *GraphPersistence) ReorderProjectItems(uid string, projectId uuid.UUID, itemIds []uuid.UUID) error {
+	log.Debug(fmt.Sprintf("reordering items of project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	ids := []string{}
+	for _, id := range itemIds {
+		ids = append(ids, id.String())
+	}
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+		"item_ids":   ids,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleEditor); err != nil {
+			return nil, err
+		}
+		query := `MATCH (p:Project {project_id: $project_id})<-[r:IN_PROJECT]-(t:TODO)
+        WITH r, t ORDER BY r.position
+        WITH collect(CASE WHEN t.item_id IN $item_ids THEN null ELSE r END) AS unlisted
+        MATCH (p:Project {project_id: $project_id})
+        WITH [id IN $item_ids | head([(:TODO {item_id: id})-[e:IN_PROJECT]->(p) | e])] AS listed, unlisted
+        WITH [r IN listed WHERE r IS NOT NULL] + unlisted AS ordered
+        UNWIND range(0, size(ordered) - 1) AS i
+        WITH ordered[i] AS r, i
+        SET r.position = i`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to reorder project items: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to delete a project. projects are either deleted
+// along with all subprojects and items (cascade), or their items and
+// subprojects are reassigned to the given target project. if no target
+// is given, they are reassigned to the parent of the deleted project.
+// the digests of attachments deleted along with items are returned
+func (db *GraphPersistence) DeleteProject(uid string, projectId uuid.UUID, cascade bool, targetId *uuid.UUID) ([]string, error) {
+	log.Debug(fmt.Sprintf("deleting project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+		"target_id":  optionalIdParam(targetId),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(p:Project {project_id: $project_id})
+        OPTIONAL MATCH (u)-[:OWNS]->(target:Project {project_id: $target_id})
+        RETURN [(p)-[:SUBPROJECT_OF]->(x:Project) | x.project_id], target IS NOT NULL,
+        target IS NOT NULL AND exists((target)-[:SUBPROJECT_OF*0..]->(p))`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrProjectNotFound
+		}
+
+		if cascade {
+			digests, err := deleteAttachments(tx, `MATCH (:Project {project_id: $project_id})<-[:SUBPROJECT_OF*0..]-(:Project)
+            <-[:IN_PROJECT]-(:TODO)<-[:ATTACHED_TO]-(a:Attachment)`, cfg)
+			if err != nil {
+				return nil, err
+			}
+			query = `MATCH (p:Project {project_id: $project_id})<-[:SUBPROJECT_OF*0..]-(sp:Project)
+            OPTIONAL MATCH (sp)<-[:IN_PROJECT]-(t:TODO)
+            OPTIONAL MATCH (t)-[:HAS_EVENT]->(e:StateChange)
+            OPTIONAL MATCH (t)<-[:SPENT_ON]-(te:TimeEntry)
+            OPTIONAL MATCH (sp)<-[:STATE_OF]-(ws:WorkflowState)
+            WITH collect(DISTINCT sp) + collect(DISTINCT ws) AS projects,
+            collect(DISTINCT t) + collect(DISTINCT e) + collect(DISTINCT te) AS items
+            FOREACH (n IN items | DETACH DELETE n)
+            FOREACH (n IN projects | DETACH DELETE n)`
+			if _, err := tx.Run(query, cfg); err != nil {
+				return nil, err
+			}
+			return digests, nil
+		}
+
+		if targetId != nil {
+			if !node.Values[1].(bool) {
+				return nil, ErrProjectNotFound
+			}
+			if node.Values[2].(bool) {
+				return nil, ErrInvalidProjectTarget
+			}
+		} else if parents := node.Values[0].([]interface{}); len(parents) > 0 {
+			cfg["target_id"] = parents[0]
+		}
+
+		// move items to end of target project and subprojects into
+		// target project. items are unassigned if there is no target
+		query = `MATCH (p:Project {project_id: $project_id})
+        OPTIONAL MATCH (target:Project {project_id: $target_id})
+        OPTIONAL MATCH (target)<-[e:IN_PROJECT]-()
+        WITH p, target, coalesce(max(e.position) + 1, 0) AS offset
+        OPTIONAL MATCH (p)<-[r:IN_PROJECT]-(t:TODO)
+        FOREACH (x IN CASE WHEN target IS NULL OR t IS NULL THEN [] ELSE [target] END |
+            CREATE (t)-[:IN_PROJECT {position: offset + r.position}]->(x))
+        WITH DISTINCT p, target
+        OPTIONAL MATCH (p)<-[:SUBPROJECT_OF]-(c:Project)
+        FOREACH (x IN CASE WHEN target IS NULL OR c IS NULL THEN [] ELSE [target] END |
+            CREATE (c)-[:SUBPROJECT_OF]->(x))
+        WITH DISTINCT p
+        OPTIONAL MATCH (p)<-[:STATE_OF]-(ws:WorkflowState)
+        DETACH DELETE ws
+        WITH DISTINCT p
+        DETACH DELETE p`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		return []string{}, nil
+	}
+	digests, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete project: %+v", err))
+		return nil, err
+	}
+	return digests.([]string), nil
+}
+
+// function used to share a project (and its subprojects) with
+// another user. collaborators that have already been added are
+// updated with the given role
+func (db *GraphPersistence) ShareProject(uid string, projectId uuid.UUID, collaborator, role string) error {
+	log.Debug(fmt.Sprintf("sharing project %s with user %s as %s", projectId, collaborator, role))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":          uid,
+		"project_id":   projectId.String(),
+		"collaborator": collaborator,
+		"role":         role,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		query := `MATCH (p:Project {project_id: $project_id}), (c:User {uid: $collaborator})
+        WHERE NOT (c)-[:OWNS]->(p)
+        MERGE (c)-[r:COLLABORATES_ON]->(p)
+        SET r.role = $role, r.shared = datetime(), r.shared_by = $uid
+        RETURN c.uid`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrInvalidCollaborator
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to share project: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve the collaborators of a project. users
+// collaborating on parent projects are returned along with the
+// project the collaboration was created on
+func (db *GraphPersistence) GetProjectCollaborators(uid string, projectId uuid.UUID) ([]Collaborator, error) {
+	log.Debug(fmt.Sprintf("retrieving collaborators of project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	collaborators := []Collaborator{}
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleViewer); err != nil {
+			return nil, err
+		}
+		query := `MATCH (p:Project {project_id: $project_id})-[:SUBPROJECT_OF*0..]->(x:Project)<-[r:COLLABORATES_ON]-(c:User)
+        RETURN c.uid, r.role, x.project_id, r.shared, r.shared_by
+        ORDER BY c.uid`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve project collaborators: %+v", err))
+		return collaborators, err
+	}
+	for _, node := range nodes {
+		sharedProjectId, _ := uuid.Parse(node.Values[2].(string))
+		collaborators = append(collaborators, Collaborator{
+			Uid:       node.Values[0].(string),
+			Role:      node.Values[1].(string),
+			ProjectId: sharedProjectId,
+			Shared:    node.Values[3].(time.Time),
+			SharedBy:  node.Values[4].(string),
+		})
+	}
+	return collaborators, nil
+}
+
+// function used to remove a collaborator from a project. owners can
+// remove any collaborator and collaborators can remove themselves.
+// items in the project assigned to the collaborator are unassigned
+func (db *GraphPersistence) RemoveProjectCollaborator(uid string, projectId uuid.UUID, collaborator string) error {
+	log.Debug(fmt.Sprintf("removing collaborator %s from project %s", collaborator, projectId))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":          uid,
+		"project_id":   projectId.String(),
+		"collaborator": collaborator,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		required := roleOwner
+		if uid == collaborator {
+			required = roleViewer
+		}
+		if err := authorizeProject(tx, uid, projectId, required); err != nil {
+			return nil, err
+		}
+		query := `MATCH (c:User {uid: $collaborator})-[r:COLLABORATES_ON]->(p:Project {project_id: $project_id})
+        DELETE r
+        WITH DISTINCT c, p
+        OPTIONAL MATCH (p)<-[:SUBPROJECT_OF*0..]-(:Project)<-[:IN_PROJECT]-(t:TODO)-[a:ASSIGNED_TO]->(c)
+        DELETE a
+        RETURN COUNT(DISTINCT c)`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if node.Values[0].(int64) == 0 {
+			return nil, ErrCollaboratorNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to remove project collaborator: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to assign a todo item to a user. items can only be
+// assigned to users that have access to the item. the item is
+// unassigned if no assignee is given
+func (db *GraphPersistence) AssignTodoItem(uid string, itemId uuid.UUID, assignee *string) error {
+	log.Debug(fmt.Sprintf("assigning TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"item_id":  itemId.String(),
+		"assignee": nil,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		if assignee != nil {
+			if err := authorizeTodoItem(tx, *assignee, itemId, false); err != nil {
+				return nil, ErrInvalidAssignee
+			}
+			cfg["assignee"] = *assignee
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})
+        SET t.updated_by = $uid, t.updated_at = datetime()
+        WITH t
+        OPTIONAL MATCH (t)-[r:ASSIGNED_TO]->()
+        DELETE r
+        WITH DISTINCT t
+        MATCH (a:User {uid: $assignee})
+        CREATE (t)-[:ASSIGNED_TO {assigned: datetime(), assigned_by: $uid}]->(a)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to assign TODO item: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// define properties returned for TODO series. properties
+// are converted into series using parseTodoSeries
+const todoSeriesProjection = `s.series_id, s.item_title, s.item_content, s.metadata,
+        s.priority, s.frequency, s.interval, s.cycle, s.start_at, s.skipped_dates,
+        s.created, head([(owner:User)-[:OWNS]->(s) | owner.uid]),
+        [(open:TODO)-[:OCCURRENCE_OF]->(s) WHERE NOT open.completed | [open.item_id, open.due_at]]`
+
+// function used to convert a list of values returned using
+// the TODO series projection into a TODO series
+func parseTodoSeries(values []interface{}) TodoSeries {
+	seriesId, _ := uuid.Parse(values[0].(string))
+	var metadata map[string]interface{}
+	// convert metadata from JSON string to struct
+	json.Unmarshal([]byte(values[3].(string)), &metadata)
+	series := TodoSeries{
+		SeriesId:     seriesId,
+		ItemTitle:    values[1].(string),
+		ItemContent:  values[2].(string),
+		Metadata:     metadata,
+		Priority:     values[4].(string),
+		Frequency:    values[5].(string),
+		Interval:     values[6].(int64),
+		Cycle:        values[7].(string),
+		StartAt:      values[8].(time.Time),
+		SkippedDates: []string{},
+		Created:      values[10].(time.Time),
+	}
+	for _, day := range values[9].([]interface{}) {
+		series.SkippedDates = append(series.SkippedDates, day.(string))
+	}
+	series.Owner, _ = values[11].(string)
+	if open := values[12].([]interface{}); len(open) > 0 {
+		occurrence := open[0].([]interface{})
+		if itemId, err := uuid.Parse(occurrence[0].(string)); err == nil {
+			series.OpenItemId = &itemId
+		}
+		series.NextDue = parseOptionalTime(occurrence[1])
+	}
+	return series
+}
+
+// function used to create a new TODO series for a given user. the
+// first occurrence of the series is created with the given due date
+func (db *GraphPersistence) CreateTodoSeries(uid string, series TodoSeries, due time.Time) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating new TODO series for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	// convert metadata to JSON string and store
+	meta, err := json.Marshal(series.Metadata)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to convert todo metadata: %+v", err))
+		return uuid.Nil, ErrInvalidTODOMetadata
+	}
+	seriesId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":          uid,
+		"series_id":    seriesId.String(),
+		"item_id":      uuid.New().String(),
+		"item_title":   series.ItemTitle,
+		"item_content": series.ItemContent,
+		"metadata":     string(meta),
+		"priority":     series.Priority,
+		"frequency":    series.Frequency,
+		"interval":     series.Interval,
+		"cycle":        series.Cycle,
+		"start_at":     series.StartAt.UTC(),
+		"due_at":       due.UTC(),
+		"properties":   metadataProperties(series.Metadata),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        CREATE (u)-[:OWNS]->(s:TodoSeries {
+            series_id: $series_id,
+            item_title: $item_title,
+            item_content: $item_content,
+            metadata: $metadata,
+            priority: $priority,
+            frequency: $frequency,
+            interval: $interval,
+            cycle: $cycle,
+            start_at: $start_at,
+            skipped_dates: [],
+            created: datetime()
+        })
+        CREATE (u)-[:OWNS]->(t:TODO {
+            item_id: $item_id,
+            item_title: $item_title,
+            item_content: $item_content,
+            created: datetime(),
+            completed: false,
+            due_at: $due_at,
+            remind_at: null,
+            priority: $priority,
+            created_by: $uid
+        })-[:OCCURRENCE_OF]->(s)
+        SET t += $properties`
+		return tx.Run(query, cfg)
+	}
+	_, err = session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create TODO series: %+v", err))
+		return uuid.Nil, err
+	}
+	return seriesId, nil
+}
+
+// function used to retrieve the TODO series of a given user
+func (db *GraphPersistence) GetTodoSeries(uid string) ([]TodoSeries, error) {
+	log.Debug(fmt.Sprintf("retrieving TODO series for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	series := []TodoSeries{}
+	cfg := map[string]interface{}{
+		"uid": uid,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(s:TodoSeries)
+        RETURN ` + todoSeriesProjection + `
+        ORDER BY s.created`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return series, err
+	}
+	for _, node := range nodes {
+		series = append(series, parseTodoSeries(node.Values))
+	}
+	return series, nil
+}
+
+// function used to retrieve a TODO series with given series ID.
+// callers are responsible for checking the owner of the series
+func (db *GraphPersistence) GetTodoSeriesById(seriesId uuid.UUID) (TodoSeries, error) {
+	log.Debug(fmt.Sprintf("retrieving TODO series %s", seriesId))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"series_id": seriesId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (s:TodoSeries {series_id: $series_id})
+        RETURN ` + todoSeriesProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrSeriesNotFound
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		return TodoSeries{}, err
+	}
+	return parseTodoSeries(node.Values), nil
+}
+
+// function used to update the definition of a TODO series. the title,
+// content, metadata and priority of the open occurrence are updated
+// with the series; schedule changes apply from the next occurrence
+func (db *GraphPersistence) UpdateTodoSeries(uid string, seriesId uuid.UUID, series TodoSeries) error {
+	log.Debug(fmt.Sprintf("updating TODO series %s for user %s", seriesId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	// convert metadata to JSON string and store
+	meta, err := json.Marshal(series.Metadata)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to convert todo metadata: %+v", err))
+		return ErrInvalidTODOMetadata
+	}
+	cfg := map[string]interface{}{
+		"uid":          uid,
+		"series_id":    seriesId.String(),
+		"item_title":   series.ItemTitle,
+		"item_content": series.ItemContent,
+		"metadata":     string(meta),
+		"priority":     series.Priority,
+		"frequency":    series.Frequency,
+		"interval":     series.Interval,
+		"cycle":        series.Cycle,
+		"start_at":     series.StartAt.UTC(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(s:TodoSeries {series_id: $series_id})
+        SET s.item_title = $item_title, s.item_content = $item_content, s.metadata = $metadata,
+        s.priority = $priority, s.frequency = $frequency, s.interval = $interval,
+        s.cycle = $cycle, s.start_at = $start_at
+        RETURN [(t:TODO {completed: false})-[:OCCURRENCE_OF]->(s) | t.item_id]`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrSeriesNotFound
+		}
+		for _, itemId := range node.Values[0].([]interface{}) {
+			cfg["item_id"] = itemId
+			query = `MATCH (t:TODO {item_id: $item_id})
+            SET t.item_title = $item_title, t.item_content = $item_content,
+            t.priority = $priority, t.updated_by = $uid, t.updated_at = datetime()`
+			if _, err := tx.Run(query, cfg); err != nil {
+				return nil, err
+			}
+			if err := setTodoItemMetadata(tx, itemId.(string), series.Metadata); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	_, err = session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update TODO series: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to create the next occurrence of a TODO series after
+// the given (previous) occurrence has been completed. occurrences are
+// owned by the owner of the series and are added to the end of the
+// project of the previous occurrence. no occurrence is created if
+// the series already has an open occurrence
+func (db *GraphPersistence) CreateSeriesOccurrence(uid string, series TodoSeries, previousId uuid.UUID, due time.Time) error {
+	log.Debug(fmt.Sprintf("creating next occurrence of TODO series %s", series.SeriesId))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":         uid,
+		"series_id":   series.SeriesId.String(),
+		"properties":  metadataProperties(series.Metadata),
+		"previous_id": previousId.String(),
+		"item_id":     uuid.New().String(),
+		"due_at":      due.UTC(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (o:User)-[:OWNS]->(s:TodoSeries {series_id: $series_id})
+        WHERE NOT exists((:TODO {completed: false})-[:OCCURRENCE_OF]->(s))
+        CREATE (o)-[:OWNS]->(t:TODO {
+            item_id: $item_id,
+            item_title: s.item_title,
+            item_content: s.item_content,
+            created: datetime(),
+            completed: false,
+            due_at: $due_at,
+            remind_at: null,
+            priority: s.priority,
+            created_by: $uid
+        })-[:OCCURRENCE_OF]->(s)
+        SET t += $properties
+        WITH t
+        MATCH (:TODO {item_id: $previous_id})-[:IN_PROJECT]->(p:Project)
+        OPTIONAL MATCH (p)<-[r:IN_PROJECT]-()
+        WITH t, p, coalesce(max(r.position) + 1, 0) AS position
+        CREATE (t)-[:IN_PROJECT {position: position}]->(p)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create TODO series occurrence: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to skip the open occurrence of a TODO series. the
+// skipped date is recorded on the series and the due date of the
+// open occurrence is rolled forward to the given date
+func (db *GraphPersistence) SkipSeriesOccurrence(uid string, seriesId uuid.UUID, skipped string, next time.Time) error {
+	log.Debug(fmt.Sprintf("skipping occurrence of TODO series %s for user %s", seriesId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":       uid,
+		"series_id": seriesId.String(),
+		"skipped":   skipped,
+		"due_at":    next.UTC(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(s:TodoSeries {series_id: $series_id})
+        MATCH (t:TODO {completed: false})-[:OCCURRENCE_OF]->(s)
+        SET s.skipped_dates = s.skipped_dates + $skipped,
+        t.due_at = $due_at, t.updated_by = $uid, t.updated_at = datetime()
+        RETURN t.item_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrNoOpenOccurrence
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to skip TODO series occurrence: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to delete a TODO series for a given user. existing
+// occurrences are kept as regular TODO items
+func (db *GraphPersistence) DeleteTodoSeries(uid string, seriesId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting TODO series %s for user %s", seriesId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":       uid,
+		"series_id": seriesId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(s:TodoSeries {series_id: $series_id})
+        WITH s, s.series_id AS series_id
+        DETACH DELETE s
+        RETURN series_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete TODO series: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to create a new tag for a given user. tag
+// names are unique across the tags of a user
+func (db *GraphPersistence) CreateTag(uid string, tag Tag) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("creating new tag for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	tagId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"tag_id":   tagId.String(),
+		"tag_name": tag.TagName,
+		"colour":   tag.Colour,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})
+        WHERE NOT exists((u)-[:OWNS]->(:Tag {tag_name: $tag_name}))
+        CREATE (u)-[:OWNS]->(g:Tag {
+            tag_id: $tag_id,
+            tag_name: $tag_name,
+            colour: $colour,
+            created: datetime()
+        })
+        RETURN g.tag_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrTagExists
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create tag: %+v", err))
+		return uuid.Nil, err
+	}
+	return tagId, nil
+}
+
+// function used to retrieve the tags of a given user along
+// with the number of items tagged with each tag
+func (db *GraphPersistence) GetTags(uid string) ([]Tag, error) {
+	log.Debug(fmt.Sprintf("retrieving tags for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	tags := []Tag{}
+	cfg := map[string]interface{}{
+		"uid": uid,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(g:Tag)
+        RETURN g.tag_id, g.tag_name, g.colour, g.created,
+        size([(g)<-[:TAGGED]-(t:TODO) | t])
+        ORDER BY g.tag_name`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return tags, err
+	}
+	for _, node := range nodes {
+		tagId, _ := uuid.Parse(node.Values[0].(string))
+		tags = append(tags, Tag{
+			TagId:   tagId,
+			TagName: node.Values[1].(string),
+			Colour:  node.Values[2].(string),
+			Created: node.Values[3].(time.Time),
+			Items:   node.Values[4].(int64),
+		})
+	}
+	return tags, nil
+}
+
+// function used to rename (and recolour) a tag of a given user
+func (db *GraphPersistence) UpdateTag(uid string, tagId uuid.UUID, tag Tag) error {
+	log.Debug(fmt.Sprintf("updating tag %s for user %s", tagId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"tag_id":   tagId.String(),
+		"tag_name": tag.TagName,
+		"colour":   tag.Colour,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:OWNS]->(g:Tag {tag_id: $tag_id})
+        RETURN exists((u)-[:OWNS]->(:Tag {tag_name: $tag_name})) AND g.tag_name <> $tag_name`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrTagNotFound
+		}
+		if node.Values[0].(bool) {
+			return nil, ErrTagExists
+		}
+		query = `MATCH (:User {uid: $uid})-[:OWNS]->(g:Tag {tag_id: $tag_id})
+        SET g.tag_name = $tag_name, g.colour = $colour`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to update tag: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to delete a tag of a given user. the
+// tag is removed from all items it is attached to
+func (db *GraphPersistence) DeleteTag(uid string, tagId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting tag %s for user %s", tagId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":    uid,
+		"tag_id": tagId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(g:Tag {tag_id: $tag_id})
+        WITH g, g.tag_id AS tag_id
+        DETACH DELETE g
+        RETURN tag_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrTagNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete tag: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to attach a tag of a given user to a todo item
+func (db *GraphPersistence) TagTodoItem(uid string, itemId, tagId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("tagging TODO item %s with tag %s for user %s", itemId, tagId, uid))
+	return db.setTodoItemTagged(uid, itemId, tagId, true)
+}
+
+// function used to remove a tag of a given user from a todo item
+func (db *GraphPersistence) UntagTodoItem(uid string, itemId, tagId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("untagging TODO item %s with tag %s for user %s", itemId, tagId, uid))
+	return db.setTodoItemTagged(uid, itemId, tagId, false)
+}
+
+// function used to attach or remove a tag from a todo item. users
+// must be able to edit the item and must own the tag
+func (db *GraphPersistence) setTodoItemTagged(uid string, itemId, tagId uuid.UUID, tagged bool) error {
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"item_id": itemId.String(),
+		"tag_id":  tagId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		query := `MATCH (:User {uid: $uid})-[:OWNS]->(g:Tag {tag_id: $tag_id})
+        RETURN g.tag_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrTagNotFound
+		}
+
+		query = `MATCH (t:TODO {item_id: $item_id}), (g:Tag {tag_id: $tag_id})
+        MERGE (t)-[:TAGGED]->(g)
+        SET t.updated_by = $uid, t.updated_at = datetime()`
+		if !tagged {
+			query = `MATCH (t:TODO {item_id: $item_id})-[r:TAGGED]->(:Tag {tag_id: $tag_id})
+            DELETE r
+            SET t.updated_by = $uid, t.updated_at = datetime()`
+		}
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to set the workflow of a project. existing items keep
+// their state if a state with the same name (and completion state)
+// exists in the new workflow, all other items are moved into the
+// initial or done state of the new workflow
+func (db *GraphPersistence) SetProjectWorkflow(uid string, projectId uuid.UUID, workflow Workflow) error {
+	log.Debug(fmt.Sprintf("setting workflow for project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	states := []map[string]interface{}{}
+	for _, state := range workflow.States {
+		states = append(states, map[string]interface{}{
+			"name":      state.Name,
+			"wip_limit": state.WIPLimit,
+			"done":      state.Done,
+			"position":  state.Position,
+		})
+	}
+	transitions := []map[string]interface{}{}
+	for _, transition := range workflow.Transitions {
+		transitions = append(transitions, map[string]interface{}{
+			"from": transition.From,
+			"to":   transition.To,
+		})
+	}
+	cfg := map[string]interface{}{
+		"uid":         uid,
+		"project_id":  projectId.String(),
+		"states":      states,
+		"transitions": transitions,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		// retrieve current states of items before replacing states
+		query := `MATCH (p:Project {project_id: $project_id})
+        WITH p, [(p)<-[:STATE_OF]-(s:WorkflowState)<-[:IN_STATE]-(t:TODO) | {item_id: t.item_id, state: s.name}] AS items
+        OPTIONAL MATCH (p)<-[:STATE_OF]-(ws:WorkflowState)
+        DETACH DELETE ws
+        RETURN DISTINCT items`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrProjectNotFound
+		}
+		cfg["items"] = node.Values[0]
+
+		query = `MATCH (p:Project {project_id: $project_id})
+        UNWIND $states AS state
+        CREATE (:WorkflowState {name: state.name, wip_limit: state.wip_limit,
+            done: state.done, position: state.position})-[:STATE_OF]->(p)
+        WITH DISTINCT p
+        SET p.initial_state = head([s IN $states WHERE NOT s.done | s.name]),
+        p.done_state = head([s IN $states WHERE s.done | s.name]),
+        p.updated_by = $uid, p.updated_at = datetime()
+        WITH p
+        UNWIND $transitions AS transition
+        MATCH (p)<-[:STATE_OF]-(a:WorkflowState {name: transition.from}),
+        (p)<-[:STATE_OF]-(b:WorkflowState {name: transition.to})
+        CREATE (a)-[:TRANSITIONS_TO]->(b)`
+		if _, err := tx.Run(query, cfg); err != nil {
+			return nil, err
+		}
+		query = `MATCH (p:Project {project_id: $project_id})
+        UNWIND $items AS item
+        MATCH (t:TODO {item_id: item.item_id})-[:IN_PROJECT]->(p)<-[:STATE_OF]-(ws:WorkflowState {name: item.state})
+        WHERE ws.done = t.completed
+        CREATE (t)-[:IN_STATE]->(ws)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to set project workflow: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve the workflow of a project
+func (db *GraphPersistence) GetProjectWorkflow(uid string, projectId uuid.UUID) (Workflow, error) {
+	log.Debug(fmt.Sprintf("retrieving workflow for project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleViewer); err != nil {
+			return nil, err
+		}
+		query := `MATCH (p:Project {project_id: $project_id})<-[:STATE_OF]-(ws:WorkflowState)
+        RETURN ws.name, ws.wip_limit, ws.done, ws.position,
+        [(ws)-[:TRANSITIONS_TO]->(next:WorkflowState) | next.name]
+        ORDER BY ws.position`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return Workflow{}, err
+	}
+	if len(nodes) == 0 {
+		return Workflow{}, ErrWorkflowNotFound
+	}
+
+	workflow := Workflow{ProjectId: projectId, States: []WorkflowState{},
+		Transitions: []WorkflowTransition{}}
+	for _, node := range nodes {
+		state := WorkflowState{
+			Name:     node.Values[0].(string),
+			WIPLimit: node.Values[1].(int64),
+			Done:     node.Values[2].(bool),
+			Position: node.Values[3].(int64),
+		}
+		workflow.States = append(workflow.States, state)
+		for _, next := range node.Values[4].([]interface{}) {
+			workflow.Transitions = append(workflow.Transitions,
+				WorkflowTransition{From: state.Name, To: next.(string)})
+		}
+	}
+	return workflow, nil
+}
+
+// function used to remove the workflow of a project. the
+// state change history of the items in the project is kept
+func (db *GraphPersistence) DeleteProjectWorkflow(uid string, projectId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting workflow for project %s for user %s", projectId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":        uid,
+		"project_id": projectId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeProject(tx, uid, projectId, roleOwner); err != nil {
+			return nil, err
+		}
+		query := `MATCH (p:Project {project_id: $project_id})<-[:STATE_OF]-(ws:WorkflowState)
+        DETACH DELETE ws
+        WITH DISTINCT p
+        SET p.initial_state = null, p.done_state = null,
+        p.updated_by = $uid, p.updated_at = datetime()
+        RETURN p.project_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete project workflow: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to move a todo item into a workflow state. the item
+// can only be moved along the transitions of the workflow of its
+// project. items moved into (or out of) a done state are completed
+// (or reopened). the recorded state change is returned
+func (db *GraphPersistence) TransitionTodoItem(uid string, itemId uuid.UUID, state string) (TodoStateChange, error) {
+	log.Debug(fmt.Sprintf("moving TODO item %s to state %s for user %s", itemId, state, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	change := TodoStateChange{EventId: uuid.New(), ItemId: itemId, ToState: state, ChangedBy: uid}
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"item_id":  itemId.String(),
+		"state":    state,
+		"event_id": change.EventId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})-[:IN_PROJECT]->(workflow:Project)
+        WHERE workflow.initial_state IS NOT NULL
+        WITH t, workflow, ` + todoItemState + ` AS current
+        OPTIONAL MATCH (workflow)<-[:STATE_OF]-(target:WorkflowState {name: $state})
+        RETURN current, target IS NOT NULL,
+        exists((workflow)<-[:STATE_OF]-(:WorkflowState {name: current})-[:TRANSITIONS_TO]->(target))`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrWorkflowNotFound
+		}
+		if !node.Values[1].(bool) {
+			return nil, ErrWorkflowStateUnknown
+		}
+		change.FromState = node.Values[0].(string)
+		if node.Values[2] != true {
+			return nil, ErrInvalidTransition
+		}
+
+		query = `MATCH (t:TODO {item_id: $item_id})-[:IN_PROJECT]->(p:Project)<-[:STATE_OF]-(target:WorkflowState {name: $state})
+        OPTIONAL MATCH (t)-[r:IN_STATE]->()
+        DELETE r
+        WITH DISTINCT t, target
+        CREATE (t)-[:IN_STATE]->(target)
+        CREATE (t)-[:HAS_EVENT]->(e:StateChange {event_id: $event_id, from_state: $from_state,
+            to_state: target.name, changed: datetime(), changed_by: $uid})
+        SET t.completed_by = CASE WHEN target.done
+        THEN coalesce(t.completed_by, $uid) ELSE null END,
+        t.completed_at = CASE WHEN target.done
+        THEN coalesce(t.completed_at, datetime()) ELSE null END,
+        t.completed = target.done, t.updated_by = $uid, t.updated_at = datetime()
+        RETURN e.changed`
+		cfg["from_state"] = change.FromState
+		results, err = tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err = neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		change.Changed = node.Values[0].(time.Time)
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to move TODO item: %+v", err))
+		return TodoStateChange{}, err
+	}
+	return change, nil
+}
+
+// function used to retrieve the state changes recorded for a todo
+// item in chronological order
+func (db *GraphPersistence) GetTodoItemHistory(uid string, itemId uuid.UUID) ([]TodoStateChange, error) {
+	log.Debug(fmt.Sprintf("retrieving history of TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	changes := []TodoStateChange{}
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"item_id": itemId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		query := `MATCH (:TODO {item_id: $item_id})-[:HAS_EVENT]->(e:StateChange)
+        RETURN e.event_id, e.from_state, e.to_state, e.changed, e.changed_by
+        ORDER BY e.changed`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return changes, err
+	}
+	for _, node := range nodes {
+		eventId, _ := uuid.Parse(node.Values[0].(string))
+		changes = append(changes, TodoStateChange{
+			EventId:   eventId,
+			ItemId:    itemId,
+			FromState: node.Values[1].(string),
+			ToState:   node.Values[2].(string),
+			Changed:   node.Values[3].(time.Time),
+			ChangedBy: node.Values[4].(string),
+		})
+	}
+	return changes, nil
+}
+
+// define properties returned for time entries. properties
+// are converted into entries using parseTimeEntry
+const timeEntryProjection = `e.entry_id, t.item_id, t.item_title, e.started, e.stopped,
+        e.note, e.manual, head([(t)-[:IN_PROJECT]->(ep:Project) | [ep.project_id, ep.project_name]]),
+        [(t)-[:TAGGED]->(et:Tag)<-[:OWNS]-(u) | et.tag_name]`
+
+// function used to convert a list of values returned using
+// the time entry projection into a time entry
+func parseTimeEntry(values []interface{}, now time.Time) TimeEntry {
+	entryId, _ := uuid.Parse(values[0].(string))
+	itemId, _ := uuid.Parse(values[1].(string))
+	entry := TimeEntry{
+		EntryId:   entryId,
+		ItemId:    itemId,
+		ItemTitle: values[2].(string),
+		Started:   values[3].(time.Time),
+		Stopped:   parseOptionalTime(values[4]),
+		Note:      values[5].(string),
+		Manual:    values[6].(bool),
+		Tags:      []string{},
+	}
+	if project, ok := values[7].([]interface{}); ok {
+		if projectId, err := uuid.Parse(project[0].(string)); err == nil {
+			entry.ProjectId = &projectId
+		}
+		entry.ProjectName = parseOptionalString(project[1])
+	}
+	for _, tag := range values[8].([]interface{}) {
+		entry.Tags = append(entry.Tags, tag.(string))
+	}
+	entry.Duration = int64(getTimeEntryEnd(entry, now).Sub(entry.Started).Seconds())
+	return entry
+}
+
+// function used to start a timer on a todo item for a given user.
+// users can only have a single running timer at any time
+func (db *GraphPersistence) StartTimer(uid string, itemId uuid.UUID, note string) (TimeEntry, error) {
+	log.Debug(fmt.Sprintf("starting timer on TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	now := time.Now().UTC()
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"item_id":  itemId.String(),
+		"entry_id": uuid.New().String(),
+		"note":     note,
+		"now":      now,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		// the running timer is stored on the user node. writing to the
+		// user node locks it, so that concurrent requests are serialized
+		query := `MATCH (u:User {uid: $uid})
+        OPTIONAL MATCH (u)-[:LOGGED]->(running:TimeEntry)
+        WHERE running.entry_id = u.running_timer AND running.stopped IS NULL
+        WITH u, running
+        SET u.running_timer = coalesce(running.entry_id, $entry_id)
+        RETURN u.running_timer = $entry_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, err
+		}
+		if !node.Values[0].(bool) {
+			return nil, ErrTimerRunning
+		}
+		query = `MATCH (u:User {uid: $uid}), (t:TODO {item_id: $item_id})
+        CREATE (u)-[:LOGGED]->(e:TimeEntry {
+            entry_id: $entry_id,
+            started: $now,
+            stopped: null,
+            note: $note,
+            manual: false,
+            created: datetime()
+        })-[:SPENT_ON]->(t)
+        RETURN ` + timeEntryProjection
+		results, err = tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return neo4j.Single(results, err)
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to start timer: %+v", err))
+		return TimeEntry{}, err
+	}
+	return parseTimeEntry(node.Values, now), nil
+}
+
+// function used to stop the running timer of a given user
+func (db *GraphPersistence) StopTimer(uid string) (TimeEntry, error) {
+	log.Debug(fmt.Sprintf("stopping timer for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	now := time.Now().UTC()
+	cfg := map[string]interface{}{
+		"uid": uid,
+		"now": now,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:LOGGED]->(e:TimeEntry)-[:SPENT_ON]->(t:TODO)
+        WHERE e.entry_id = u.running_timer AND e.stopped IS NULL
+        SET e.stopped = $now
+        REMOVE u.running_timer
+        RETURN ` + timeEntryProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrNoRunningTimer
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to stop timer: %+v", err))
+		return TimeEntry{}, err
+	}
+	return parseTimeEntry(node.Values, now), nil
+}
+
+// function used to retrieve the running timer of a given user
+func (db *GraphPersistence) GetRunningTimer(uid string) (TimeEntry, error) {
+	log.Debug(fmt.Sprintf("retrieving running timer for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid": uid,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:LOGGED]->(e:TimeEntry)-[:SPENT_ON]->(t:TODO)
+        WHERE e.entry_id = u.running_timer AND e.stopped IS NULL
+        RETURN ` + timeEntryProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrNoRunningTimer
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return parseTimeEntry(node.Values, time.Now().UTC()), nil
+}
+
+// function used to log a manual time entry on a todo item
+func (db *GraphPersistence) CreateTimeEntry(uid string, itemId uuid.UUID, entry TimeEntry) (uuid.UUID, error) {
+	log.Debug(fmt.Sprintf("logging time on TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	entryId := uuid.New()
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"item_id":  itemId.String(),
+		"entry_id": entryId.String(),
+		"started":  entry.Started.UTC(),
+		"stopped":  optionalTimeParam(entry.Stopped),
+		"note":     entry.Note,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		query := `MATCH (u:User {uid: $uid}), (t:TODO {item_id: $item_id})
+        CREATE (u)-[:LOGGED]->(:TimeEntry {
+            entry_id: $entry_id,
+            started: $started,
+            stopped: $stopped,
+            note: $note,
+            manual: true,
+            created: datetime()
+        })-[:SPENT_ON]->(t)`
+		return tx.Run(query, cfg)
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create time entry: %+v", err))
+		return uuid.Nil, err
+	}
+	return entryId, nil
+}
+
+// function used to delete a time entry of a given user. running
+// timers are stopped when their time entry is deleted
+func (db *GraphPersistence) DeleteTimeEntry(uid string, entryId uuid.UUID) error {
+	log.Debug(fmt.Sprintf("deleting time entry %s for user %s", entryId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid":      uid,
+		"entry_id": entryId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:LOGGED]->(e:TimeEntry {entry_id: $entry_id})
+        SET u.running_timer = CASE WHEN u.running_timer = $entry_id THEN null ELSE u.running_timer END
+        DETACH DELETE e
+        RETURN $entry_id`
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := neo4j.Single(results, err); err != nil {
+			return nil, ErrTimeEntryNotFound
+		}
+		return nil, nil
+	}
+	_, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete time entry: %+v", err))
+		return err
+	}
+	return nil
+}
+
+// function used to retrieve the time entries of a given user. entries
+// can be filtered by item and by the window they have been started in
+func (db *GraphPersistence) GetTimeEntries(uid string, filter TimeEntryFilter) ([]TimeEntry, error) {
+	log.Debug(fmt.Sprintf("retrieving time entries for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	now := time.Now().UTC()
+	entries := []TimeEntry{}
+	cfg := map[string]interface{}{
+		"uid":     uid,
+		"item_id": optionalIdParam(filter.ItemId),
+		"from":    optionalTimeParam(filter.From),
+		"to":      optionalTimeParam(filter.To),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `MATCH (u:User {uid: $uid})-[:LOGGED]->(e:TimeEntry)-[:SPENT_ON]->(t:TODO)
+        WHERE ($item_id IS NULL OR t.item_id = $item_id)
+        AND ($from IS NULL OR e.started >= $from)
+        AND ($to IS NULL OR e.started < $to)
+        RETURN ` + timeEntryProjection + `
+        ORDER BY e.started`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return entries, err
+	}
+	for _, node := range nodes {
+		entries = append(entries, parseTimeEntry(node.Values, now))
+	}
+	return entries, nil
+}
+
+// define projection used to return attachments (requires a and t)
+const attachmentProjection = `a.attachment_id, t.item_id, a.file_name, a.content_type,
+        a.size, a.digest, a.uploaded, a.uploaded_by`
+
+// function used to parse an attachment from a set of values
+// returned by the attachment projection
+func parseAttachment(values []interface{}) Attachment {
+	attachmentId, _ := uuid.Parse(values[0].(string))
+	itemId, _ := uuid.Parse(values[1].(string))
+	return Attachment{
+		AttachmentId: attachmentId,
+		ItemId:       itemId,
+		FileName:     values[2].(string),
+		ContentType:  values[3].(string),
+		Size:         values[4].(int64),
+		Digest:       values[5].(string),
+		Uploaded:     values[6].(time.Time),
+		UploadedBy:   values[7].(string),
+	}
+}
+
+// function used to retrieve the total size of all
+// attachments uploaded by a given user
+func (db *GraphPersistence) GetAttachmentUsage(uid string) (int64, error) {
+	log.Debug(fmt.Sprintf("retrieving attachment usage for user %s", uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"uid": uid,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		query := `OPTIONAL MATCH (a:Attachment {uploaded_by: $uid})
+        RETURN coalesce(sum(a.size), 0)`
+		return neo4j.Single(tx.Run(query, cfg))
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return 0, err
+	}
+	return node.Values[0].(int64), nil
+}
+
+// function used to attach a file to a todo item. the blob is
+// stored once the attachment has been created. attachments that
+// would exceed the storage quota of the uploading user are rejected
+func (db *GraphPersistence) CreateAttachment(uid string, attachment Attachment, quota int64) (Attachment, error) {
+	log.Debug(fmt.Sprintf("attaching file to TODO item %s for user %s", attachment.ItemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	attachment.AttachmentId = uuid.New()
+	attachment.UploadedBy = uid
+	cfg := map[string]interface{}{
+		"uid":           uid,
+		"item_id":       attachment.ItemId.String(),
+		"attachment_id": attachment.AttachmentId.String(),
+		"file_name":     attachment.FileName,
+		"content_type":  attachment.ContentType,
+		"size":          attachment.Size,
+		"digest":        attachment.Digest,
+		"quota":         quota,
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, attachment.ItemId, true); err != nil {
+			return nil, err
+		}
+		// the blob node is locked so that the blob is not deleted
+		// by a concurrent cleanup before it has been written
+		query := `OPTIONAL MATCH (a:Attachment {uploaded_by: $uid})
+        WITH coalesce(sum(a.size), 0) AS usage
+        WHERE usage + $size <= $quota
+        MATCH (t:TODO {item_id: $item_id})
+        MERGE (b:Blob {digest: $digest})
+        SET b.locked = datetime()
+        WITH t
+        CREATE (a:Attachment {
+            attachment_id: $attachment_id,
+            file_name: $file_name,
+            content_type: $content_type,
+            size: $size,
+            digest: $digest,
+            uploaded: datetime(),
+            uploaded_by: $uid
+        })-[:ATTACHED_TO]->(t)
+        RETURN ` + attachmentProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrAttachmentQuotaExceeded
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.WriteTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to create attachment: %+v", err))
+		return attachment, err
+	}
+	return parseAttachment(node.Values), nil
+}
+
+// function used to retrieve the attachments of a todo item
+func (db *GraphPersistence) GetAttachments(uid string, itemId uuid.UUID) ([]Attachment, error) {
+	log.Debug(fmt.Sprintf("retrieving attachments of TODO item %s for user %s", itemId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	attachments := []Attachment{}
+	cfg := map[string]interface{}{
+		"item_id": itemId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})<-[:ATTACHED_TO]-(a:Attachment)
+        RETURN ` + attachmentProjection + `
+        ORDER BY a.uploaded`
+		return neo4j.Collect(tx.Run(query, cfg))
+	}
+	nodes, err := neo4j.AsRecords(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to execute graph query: %+v", err))
+		return attachments, err
+	}
+	for _, node := range nodes {
+		attachments = append(attachments, parseAttachment(node.Values))
+	}
+	return attachments, nil
+}
+
+// function used to retrieve a single attachment of a todo item
+func (db *GraphPersistence) GetAttachment(uid string, itemId, attachmentId uuid.UUID) (Attachment, error) {
+	log.Debug(fmt.Sprintf("retrieving attachment %s for user %s", attachmentId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"item_id":       itemId.String(),
+		"attachment_id": attachmentId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, false); err != nil {
+			return nil, err
+		}
+		query := `MATCH (t:TODO {item_id: $item_id})<-[:ATTACHED_TO]-(a:Attachment {attachment_id: $attachment_id})
+        RETURN ` + attachmentProjection
+		results, err := tx.Run(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		node, err := neo4j.Single(results, err)
+		if err != nil {
+			return nil, ErrAttachmentNotFound
+		}
+		return node, nil
+	}
+	node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve attachment: %+v", err))
+		return Attachment{}, err
+	}
+	return parseAttachment(node.Values), nil
+}
+
+// function used to delete an attachment of a todo item. the
+// digest of the deleted attachment is returned so that the blob
+// can be removed from the blob store if it is no longer referenced
+func (db *GraphPersistence) DeleteAttachment(uid string, itemId, attachmentId uuid.UUID) (string, error) {
+	log.Debug(fmt.Sprintf("deleting attachment %s for user %s", attachmentId, uid))
+	session := db.NewSession()
+	defer session.Close()
+
+	cfg := map[string]interface{}{
+		"item_id":       itemId.String(),
+		"attachment_id": attachmentId.String(),
+	}
+	handler := func(tx neo4j.Transaction) (interface{}, error) {
+		if err := authorizeTodoItem(tx, uid, itemId, true); err != nil {
+			return nil, err
+		}
+		digests, err := deleteAttachments(tx, `MATCH (:TODO {item_id: $item_id})
+            <-[:ATTACHED_TO]-(a:Attachment {attachment_id: $attachment_id})`, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if len(digests) == 0 {
+			return nil, ErrAttachmentNotFound
+		}
+		return digests[0], nil
+	}
+	digest, err := session.WriteTransaction(handler)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to delete attachment: %+v", err))
+		return "", err
+	}
+	return digest.(string), nil
+}
+
+// function used to delete blobs that are no longer referenced by
+// attachments. the blob node of each digest is locked while references
+// are checked and the blob is removed using the given function, so
+// that blobs cannot be removed while being attached on other replicas
+func (db *GraphPersistence) DeleteUnreferencedBlobs(digests []string, remove func(string) error) error {
+	log.Debug(fmt.Sprintf("deleting %d unreferenced blob(s)", len(digests)))
+	session := db.NewSession()
+	defer session.Close()
+
+	for _, digest := range digests {
+		cfg := map[string]interface{}{
+			"digest": digest,
+		}
+		handler := func(tx neo4j.Transaction) (interface{}, error) {
+			query := `MERGE (b:Blob {digest: $digest})
+            SET b.locked = datetime()
+            RETURN size([(a:Attachment {digest: $digest}) | a]) > 0`
+			results, err := tx.Run(query, cfg)
+			if err != nil {
+				return nil, err
+			}
+			node, err := neo4j.Single(results, err)
+			if err != nil {
+				return nil, err
+			}
+			if node.Values[0].(bool) {
+				return nil, nil
+			}
+			if err := remove(digest); err != nil {
+				return nil, err
+			}
+			return tx.Run(`MATCH (b:Blob {digest: $digest}) DELETE b`, cfg)
+		}
+		if _, err := session.WriteTransaction(handler); err != nil {
+			log.Error(fmt.Errorf("unable to delete blob %s: %+v", digest, err))
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,286 @@
+package todo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+const (
+	// define times of day assigned to quick-add items
+	// with a due date but without an explicit time
+	quickAddDefaultTime = time.Hour * 9
+	quickAddTonightTime = time.Hour * 20
+)
+
+// define pattern used to parse times of day (e.g. 5pm, 5:30pm or 17:00)
+var quickAddTimePattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+
+// define mappings for weekdays used in quick-add input
+var quickAddWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// struct used to return the interpretation of quick-add input.
+// the project is given by name and resolved against the projects
+// of the user when the item is created
+type QuickAddResult struct {
+	ItemId    uuid.UUID  `json:"item_id"`
+	Input     string     `json:"input"`
+	ItemTitle string     `json:"item_title"`
+	DueAt     *time.Time `json:"due_at"`
+	Tags      []string   `json:"tags"`
+	Priority  string     `json:"priority"`
+	Project   *string    `json:"project"`
+	ProjectId *uuid.UUID `json:"project_id"`
+}
+
+// struct used to collect the date and time parts of quick-add input
+type quickAddDue struct {
+	date     *time.Time
+	clock    *time.Duration
+	absolute *time.Time
+	tonight  bool
+}
+
+// function used to parse a time of day. bare numbers are only
+// treated as times if they are explicitly preceded by "at". hours
+// from 1 to 7 without am/pm (e.g. at 5) are assumed to refer to the
+// afternoon, while hours given with a leading zero (e.g. 05:00) are
+// always read as 24-hour times
+func parseQuickAddClock(word string, explicit bool) (time.Duration, bool) {
+	if word == "noon" {
+		return time.Hour * 12, true
+	}
+	match := quickAddTimePattern.FindStringSubmatch(word)
+	if match == nil || (!explicit && match[2] == "" && match[3] == "") {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	switch {
+	case match[3] != "" && (hour < 1 || hour > 12):
+		return 0, false
+	case match[3] == "pm" && hour != 12:
+		hour += 12
+	case match[3] == "am" && hour == 12:
+		hour = 0
+	case match[3] == "" && len(match[1]) == 1 && hour >= 1 && hour <= 7:
+		hour += 12
+	}
+	if hour > 23 || minute > 59 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// function used to evaluate the date of a weekday. weekdays
+// refer to the next occurrence of the weekday after today
+func getQuickAddWeekday(today time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// function used to match date and time expressions at the given
+// position of quick-add input. the number of words consumed by the
+// expression is returned, or zero if no expression was matched
+func matchQuickAddDue(words []string, i int, today time.Time, now time.Time, due *quickAddDue) int {
+	word := words[i]
+	next := ""
+	if i+1 < len(words) {
+		next = words[i+1]
+	}
+	setDate := func(date time.Time) { due.date = &date }
+
+	switch word {
+	case "today":
+		setDate(today)
+		return 1
+	case "tonight":
+		setDate(today)
+		due.tonight = true
+		return 1
+	case "tomorrow", "tmr":
+		setDate(today.AddDate(0, 0, 1))
+		return 1
+	case "next", "on":
+		// abbreviated weekdays (e.g. sun or sat) are only treated
+		// as dates when preceded by next or on
+		if weekday, ok := quickAddWeekdays[next]; ok {
+			setDate(getQuickAddWeekday(today, weekday))
+			return 2
+		}
+	case "at":
+		if clock, ok := parseQuickAddClock(next, true); ok {
+			due.clock = &clock
+			return 2
+		}
+	case "in":
+		// relative due dates (e.g. in 3 days or in 2 hours)
+		if i+2 >= len(words) {
+			return 0
+		}
+		n, err := strconv.Atoi(next)
+		if err != nil || n < 1 {
+			return 0
+		}
+		switch strings.TrimSuffix(words[i+2], "s") {
+		case "minute", "min":
+			absolute := now.Add(time.Duration(n) * time.Minute)
+			due.absolute = &absolute
+		case "hour":
+			absolute := now.Add(time.Duration(n) * time.Hour)
+			due.absolute = &absolute
+		case "day":
+			setDate(today.AddDate(0, 0, n))
+		case "week":
+			setDate(today.AddDate(0, 0, 7*n))
+		case "month":
+			setDate(today.AddDate(0, n, 0))
+		default:
+			return 0
+		}
+		return 3
+	}
+
+	if weekday, ok := quickAddWeekdays[word]; ok && len(word) > 3 {
+		setDate(getQuickAddWeekday(today, weekday))
+		return 1
+	}
+	if date, err := time.ParseInLocation(seriesDateFormat, word, today.Location()); err == nil {
+		setDate(date)
+		return 1
+	}
+	if clock, ok := parseQuickAddClock(word, false); ok {
+		due.clock = &clock
+		return 1
+	}
+	return 0
+}
+
+// function used to parse quick-add input into a TODO item. tags are
+// given as #tag, priorities as !priority and projects as +Project
+// (with underscores in place of spaces). due dates and times are
+// evaluated relative to the given time in the location of the user.
+// all remaining words are used as the title of the item
+func parseQuickAdd(input string, now time.Time, loc *time.Location) (QuickAddResult, error) {
+	result := QuickAddResult{Input: input, Tags: []string{}, Priority: defaultTodoPriority}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var due quickAddDue
+	title := []string{}
+	words := strings.Fields(input)
+	// words are matched in lowercase without trailing punctuation
+	normalized := make([]string, len(words))
+	for i, word := range words {
+		normalized[i] = strings.TrimRight(strings.ToLower(word), ",.;")
+	}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case strings.HasPrefix(word, "#") && len(word) > 1:
+			tag, err := normalizeTagName(word[1:])
+			if err != nil {
+				return result, err
+			}
+			if !utils.StringSliceContains(result.Tags, tag) {
+				result.Tags = append(result.Tags, tag)
+			}
+			continue
+		case strings.HasPrefix(word, "!") && utils.StringSliceContains(todoPriorities, normalized[i][1:]):
+			result.Priority = normalized[i][1:]
+			continue
+		case strings.HasPrefix(word, "+") && len(word) > 1:
+			project := strings.ReplaceAll(word[1:], "_", " ")
+			result.Project = &project
+			continue
+		}
+		if n := matchQuickAddDue(normalized, i, today, now, &due); n > 0 {
+			i += n - 1
+			continue
+		}
+		title = append(title, word)
+	}
+
+	result.ItemTitle = strings.Join(title, " ")
+	if len(result.ItemTitle) == 0 {
+		return result, ErrInvalidTODOTitle
+	}
+
+	switch {
+	case due.absolute != nil:
+		dueAt := due.absolute.UTC()
+		result.DueAt = &dueAt
+	case due.date != nil || due.clock != nil:
+		clock := quickAddDefaultTime
+		if due.tonight {
+			clock = quickAddTonightTime
+		}
+		if due.clock != nil {
+			clock = *due.clock
+		}
+		date := today
+		if due.date != nil {
+			date = *due.date
+		}
+		dueAt := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc).Add(clock)
+		// times without a date refer to the next occurrence of the time
+		if due.date == nil && !dueAt.After(now) {
+			dueAt = dueAt.AddDate(0, 0, 1)
+		}
+		dueAt = dueAt.UTC()
+		result.DueAt = &dueAt
+	}
+	return result, nil
+}
+
+// function used to create a TODO item from quick-add input. projects
+// are matched by name (ignoring case) against the projects of the user
+func quickAddTodoItem(uid, input string) (QuickAddResult, error) {
+	result, err := parseQuickAdd(input, time.Now().UTC(), getUserLocation(uid))
+	if err != nil {
+		return result, err
+	}
+	if result.Project != nil {
+		projects, err := persistence.GetProjects(uid, false)
+		if err != nil {
+			return result, err
+		}
+		for _, project := range projects {
+			if strings.EqualFold(project.ProjectName, *result.Project) {
+				projectId := project.ProjectId
+				result.ProjectId = &projectId
+				break
+			}
+		}
+		if result.ProjectId == nil {
+			return result, ErrProjectNotFound
+		}
+	}
+
+	item := TODOItem{
+		ItemTitle: result.ItemTitle,
+		Metadata:  map[string]interface{}{},
+		DueAt:     result.DueAt,
+		Priority:  result.Priority,
+		Tags:      result.Tags,
+		ProjectId: result.ProjectId,
+	}
+	result.ItemId, err = persistence.CreateTodoItem(uid, item)
+	return result, err
+}
@@ -0,0 +1,193 @@
+package todo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 2024-01-01 is a monday
+var quickAddNow = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// function used to generate due dates in test cases
+func quickAddDate(month time.Month, day, hour, minute int) *time.Time {
+	ts := time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	return &ts
+}
+
+func TestParseQuickAdd(t *testing.T) {
+	cases := []struct {
+		name         string
+		input        string
+		wantTitle    string
+		wantDue      *time.Time
+		wantTags     []string
+		wantPriority string
+		wantProject  string
+	}{
+		{
+			name:      "plain title",
+			input:     "Buy milk",
+			wantTitle: "Buy milk",
+		},
+		{
+			name:      "abbreviated weekday in title",
+			input:     "Buy sun cream",
+			wantTitle: "Buy sun cream",
+		},
+		{
+			name:      "abbreviated weekday after on",
+			input:     "Buy milk on sun",
+			wantTitle: "Buy milk",
+			wantDue:   quickAddDate(time.January, 7, 9, 0),
+		},
+		{
+			name:      "abbreviated weekday after next",
+			input:     "Water plants next wed",
+			wantTitle: "Water plants",
+			wantDue:   quickAddDate(time.January, 3, 9, 0),
+		},
+		{
+			name:      "full weekday",
+			input:     "Pay rent friday",
+			wantTitle: "Pay rent",
+			wantDue:   quickAddDate(time.January, 5, 9, 0),
+		},
+		{
+			name:      "current weekday refers to next week",
+			input:     "Team lunch monday",
+			wantTitle: "Team lunch",
+			wantDue:   quickAddDate(time.January, 8, 9, 0),
+		},
+		{
+			name:      "bare hour assumed to be afternoon",
+			input:     "Submit report at 5",
+			wantTitle: "Submit report",
+			wantDue:   quickAddDate(time.January, 1, 17, 0),
+		},
+		{
+			name:      "explicit morning time in the past",
+			input:     "Submit report at 5am",
+			wantTitle: "Submit report",
+			wantDue:   quickAddDate(time.January, 2, 5, 0),
+		},
+		{
+			name:      "24-hour time with leading zero",
+			input:     "Gym at 05:00",
+			wantTitle: "Gym",
+			wantDue:   quickAddDate(time.January, 2, 5, 0),
+		},
+		{
+			name:      "bare hour after noon",
+			input:     "Call bank at 11",
+			wantTitle: "Call bank",
+			wantDue:   quickAddDate(time.January, 1, 11, 0),
+		},
+		{
+			name:      "date and time",
+			input:     "Meeting tomorrow at 10:30",
+			wantTitle: "Meeting",
+			wantDue:   quickAddDate(time.January, 2, 10, 30),
+		},
+		{
+			name:      "weekday and afternoon time",
+			input:     "Call mom next sat at 5",
+			wantTitle: "Call mom",
+			wantDue:   quickAddDate(time.January, 6, 17, 0),
+		},
+		{
+			name:         "tonight with tags, priority and project",
+			input:        "Cook dinner tonight #family #Family !high +Home_Chores",
+			wantTitle:    "Cook dinner",
+			wantDue:      quickAddDate(time.January, 1, 20, 0),
+			wantTags:     []string{"family"},
+			wantPriority: "high",
+			wantProject:  "Home Chores",
+		},
+		{
+			name:      "relative minutes",
+			input:     "Check oven in 30 minutes",
+			wantTitle: "Check oven",
+			wantDue:   quickAddDate(time.January, 1, 10, 30),
+		},
+		{
+			name:      "relative days",
+			input:     "Follow up in 3 days",
+			wantTitle: "Follow up",
+			wantDue:   quickAddDate(time.January, 4, 9, 0),
+		},
+		{
+			name:      "ISO date",
+			input:     "Renew passport 2024-03-15",
+			wantTitle: "Renew passport",
+			wantDue:   quickAddDate(time.March, 15, 9, 0),
+		},
+		{
+			name:      "bare number is kept in title",
+			input:     "Read chapter 12",
+			wantTitle: "Read chapter 12",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseQuickAdd(tc.input, quickAddNow, time.UTC)
+			if err != nil {
+				t.Fatalf("unable to parse input %q: %+v", tc.input, err)
+			}
+			if result.ItemTitle != tc.wantTitle {
+				t.Errorf("expected title %q but got %q", tc.wantTitle, result.ItemTitle)
+			}
+			switch {
+			case tc.wantDue == nil && result.DueAt != nil:
+				t.Errorf("expected no due date but got %s", result.DueAt)
+			case tc.wantDue != nil && (result.DueAt == nil || !result.DueAt.Equal(*tc.wantDue)):
+				t.Errorf("expected due date %s but got %v", tc.wantDue, result.DueAt)
+			}
+			wantTags := tc.wantTags
+			if wantTags == nil {
+				wantTags = []string{}
+			}
+			if !reflect.DeepEqual(result.Tags, wantTags) {
+				t.Errorf("expected tags %v but got %v", wantTags, result.Tags)
+			}
+			wantPriority := tc.wantPriority
+			if len(wantPriority) == 0 {
+				wantPriority = defaultTodoPriority
+			}
+			if result.Priority != wantPriority {
+				t.Errorf("expected priority %s but got %s", wantPriority, result.Priority)
+			}
+			project := ""
+			if result.Project != nil {
+				project = *result.Project
+			}
+			if project != tc.wantProject {
+				t.Errorf("expected project %q but got %q", tc.wantProject, project)
+			}
+		})
+	}
+}
+
+func TestParseQuickAddUserTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %+v", err)
+	}
+	// 2024-01-01 03:00 UTC is still 2023-12-31 in new york
+	now := time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)
+	result, err := parseQuickAdd("Standup tomorrow at 9am", now, loc)
+	if err != nil {
+		t.Fatalf("unable to parse input: %+v", err)
+	}
+	want := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	if result.DueAt == nil || !result.DueAt.Equal(want) {
+		t.Errorf("expected due date %s but got %v", want, result.DueAt)
+	}
+}
+
+func TestParseQuickAddWithoutTitle(t *testing.T) {
+	if _, err := parseQuickAdd("#errands !high tomorrow", quickAddNow, time.UTC); err != ErrInvalidTODOTitle {
+		t.Errorf("expected %+v but got %+v", ErrInvalidTODOTitle, err)
+	}
+}
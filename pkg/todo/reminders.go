@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+// function used to periodically deliver reminders for TODO items
+// whose reminder time has passed. reminders are delivered using the
+// given notifier. the worker blocks and should be run in a goroutine
+func RunReminderWorker(interval time.Duration, notifier utils.Notifier) {
+	log.Info(fmt.Sprintf("starting TODO reminder worker with interval %s", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		deliverTodoReminders(notifier)
+		<-ticker.C
+	}
+}
+
+// function used to deliver all pending TODO reminders. reminders
+// are marked as sent once delivered so that they are sent once
+func deliverTodoReminders(notifier utils.Notifier) {
+	now := time.Now().UTC()
+	reminders, err := persistence.GetPendingTodoReminders(now)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve pending TODO reminders: %+v", err))
+		return
+	}
+	for _, reminder := range reminders {
+		item := reminder.Item
+		notification := utils.Notification{
+			Uid:     reminder.Uid,
+			Source:  "todo",
+			Subject: fmt.Sprintf("Reminder: %s", item.ItemTitle),
+			Message: item.ItemContent,
+			Data: map[string]interface{}{
+				"item_id":  item.ItemId,
+				"due_at":   item.DueAt,
+				"priority": item.Priority,
+				"overdue":  item.Overdue,
+			},
+			Created: now,
+		}
+		if err := notifier.Notify(notification); err != nil {
+			log.Error(fmt.Errorf("unable to deliver reminder for TODO item %s: %+v", item.ItemId, err))
+			continue
+		}
+		if err := persistence.MarkTodoReminderSent(item.ItemId, now); err != nil {
+			log.Error(fmt.Errorf("unable to mark reminder for TODO item %s as sent: %+v", item.ItemId, err))
+		}
+	}
+}
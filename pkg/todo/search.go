@@ -0,0 +1,108 @@
+package todo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// define maximum page size for TODO items. all
+	// items are returned if no page size is given
+	maxTodoPageSize = 1000
+	// define maximum number of accessible items matched by
+	// a text query, taken in order of relevance
+	maxTodoSearchResults = 1000
+)
+
+// define expressions used to evaluate the sort key of a TODO item (t)
+// for each of the fields items can be sorted by. items are always sorted
+// by item ID within equal sort keys so that cursors are stable. items
+// without due dates are sorted last in both directions and priorities
+// are ranked so that urgent items are sorted first
+var todoSortKeys = map[string]string{
+	"created": `t.created`,
+	"due": `coalesce(t.due_at, CASE WHEN $descending
+        THEN datetime('0001-01-01T00:00:00Z') ELSE datetime('9999-12-31T00:00:00Z') END)`,
+	"priority": `CASE coalesce(t.priority, 'medium') WHEN 'urgent' THEN 0
+        WHEN 'high' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END`,
+	"position": `head([(t)-[m:IN_PROJECT]->(:Project {project_id: $project}) | m.position])`,
+}
+
+// define characters with special meaning in full-text queries
+const searchSpecialCharacters = `+-&|!(){}[]^"~*?:\/`
+
+// struct used to store the position of a page of TODO items.
+// cursors are passed to clients as opaque base64 strings
+type todoItemCursor struct {
+	Key    interface{} `json:"key"`
+	ItemId uuid.UUID   `json:"item_id"`
+}
+
+// function used to escape a user query for the full-text index.
+// all terms are matched literally
+func escapeSearchQuery(query string) string {
+	var escaped strings.Builder
+	for _, c := range query {
+		if strings.ContainsRune(searchSpecialCharacters, c) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(c)
+	}
+	return escaped.String()
+}
+
+// function used to convert a user query into a full-text query.
+// queries are trimmed and escaped, and lowercased so that terms
+// such as AND, OR and NOT are not parsed as operators
+func normalizeSearchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if len(query) == 0 {
+		return query, ErrInvalidTODOQuery
+	}
+	return strings.ToLower(escapeSearchQuery(query)), nil
+}
+
+// function used to encode the sort key and item ID of
+// the last item of a page into a cursor
+func encodeTodoItemCursor(key interface{}, itemId uuid.UUID) string {
+	if ts, ok := key.(time.Time); ok {
+		key = ts.UTC().Format(time.RFC3339Nano)
+	}
+	cursor, _ := json.Marshal(todoItemCursor{Key: key, ItemId: itemId})
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+// function used to decode a cursor for a given sort field. sort
+// keys are converted back into timestamps or integer ranks
+func decodeTodoItemCursor(value, sort string) (interface{}, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, uuid.Nil, ErrInvalidTODOCursor
+	}
+	var cursor todoItemCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, uuid.Nil, ErrInvalidTODOCursor
+	}
+	switch key := cursor.Key.(type) {
+	case string:
+		if sort != "created" && sort != "due" {
+			return nil, uuid.Nil, ErrInvalidTODOCursor
+		}
+		ts, err := time.Parse(time.RFC3339Nano, key)
+		if err != nil {
+			return nil, uuid.Nil, ErrInvalidTODOCursor
+		}
+		return ts, cursor.ItemId, nil
+	case float64:
+		if sort != "priority" && sort != "position" {
+			return nil, uuid.Nil, ErrInvalidTODOCursor
+		}
+		return int64(key), cursor.ItemId, nil
+	default:
+		return nil, uuid.Nil, ErrInvalidTODOCursor
+	}
+}
@@ -0,0 +1,197 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+const (
+	// define date format used for skipped occurrences
+	seriesDateFormat = "2006-01-02"
+)
+
+// define valid frequencies for TODO series. daily and monthly
+// series repeat every interval days or months, while weekly
+// series repeat on the weekdays given in their cycle
+var seriesFrequencies = []string{"daily", "weekly", "monthly"}
+
+// function used to validate a TODO series definition. series are
+// given an interval of one if no interval is set
+func validateTodoSeries(series *TodoSeries) error {
+	if len(series.ItemTitle) == 0 || series.StartAt.IsZero() {
+		return ErrInvalidSeries
+	}
+	if !utils.StringSliceContains(seriesFrequencies, series.Frequency) {
+		log.Warn(fmt.Sprintf("received invalid series frequency %s", series.Frequency))
+		return ErrInvalidSeries
+	}
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+	if series.Interval < 0 {
+		return ErrInvalidSeries
+	}
+	if series.Frequency == "weekly" {
+		cycle := strings.Split(strings.ToLower(series.Cycle), ",")
+		for _, day := range cycle {
+			if !utils.StringSliceContains(utils.ValidCycles, day) {
+				log.Warn(fmt.Sprintf("received invalid series cycle %s", series.Cycle))
+				return ErrInvalidSeries
+			}
+		}
+		series.Cycle = strings.Join(cycle, ",")
+	}
+	if len(series.Priority) == 0 {
+		series.Priority = defaultTodoPriority
+	}
+	if !utils.StringSliceContains(todoPriorities, series.Priority) {
+		return ErrInvalidTODOPriority
+	}
+	return validateTodoMetadata(series.Metadata)
+}
+
+// function used to determine if the occurrence of a series
+// on the day of the given timestamp has been skipped
+func seriesDaySkipped(series TodoSeries, ts time.Time) bool {
+	return utils.StringSliceContains(series.SkippedDates, ts.UTC().Format(seriesDateFormat))
+}
+
+// function used to determine if a series occurs on the day of
+// the given timestamp. only used for weekly series
+func seriesDayInCycle(series TodoSeries, ts time.Time) bool {
+	cycle := strings.Split(series.Cycle, ",")
+	return utils.StringSliceContains(cycle, utils.ReverseCycleMappings[int(ts.UTC().Weekday())])
+}
+
+// function used to add a number of months to the start of a series.
+// days that do not exist in the target month are clamped to the
+// last day of the month (e.g. the 31st becomes the 30th)
+func addSeriesMonths(start time.Time, months int) time.Time {
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1,
+		start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// function used to evaluate the first due date of a series. the
+// start of the series is used if it is a valid, non-skipped date
+func getFirstOccurrence(series TodoSeries) time.Time {
+	first := series.StartAt.UTC()
+	if (series.Frequency != "weekly" || seriesDayInCycle(series, first)) && !seriesDaySkipped(series, first) {
+		return first
+	}
+	return getNextOccurrence(series, first)
+}
+
+// function used to evaluate the due date of the occurrence of a
+// series following the given due date. skipped occurrences are
+// stepped over in the same way excused days are for habits
+func getNextOccurrence(series TodoSeries, previous time.Time) time.Time {
+	start := series.StartAt.UTC()
+	ts := previous.UTC()
+	for {
+		switch series.Frequency {
+		case "daily":
+			ts = ts.AddDate(0, 0, int(series.Interval))
+		case "weekly":
+			// step forward until next day in required cycle
+			ts = ts.AddDate(0, 0, 1)
+			for !seriesDayInCycle(series, ts) {
+				ts = ts.AddDate(0, 0, 1)
+			}
+		case "monthly":
+			months := (ts.Year()-start.Year())*12 + int(ts.Month()-start.Month())
+			ts = addSeriesMonths(start, months+int(series.Interval))
+		}
+		if !seriesDaySkipped(series, ts) {
+			return ts
+		}
+	}
+}
+
+// function used to complete a TODO item. if the item is the open
+// occurrence of a series, the next occurrence is created with its
+// due date rolled forward
+func completeTodoItem(uid string, itemId uuid.UUID) error {
+	item, err := persistence.GetTodoItem(uid, itemId)
+	if err != nil {
+		return err
+	}
+	if err := persistence.CompleteTodoItem(uid, itemId); err != nil {
+		return err
+	}
+	// items that were already completed have already
+	// created the next occurrence of their series
+	if item.Completed || item.SeriesId == nil {
+		return nil
+	}
+	return scheduleNextOccurrence(uid, item)
+}
+
+// function used to create the next occurrence of a series once
+// the given (open) occurrence of the series has been completed
+func scheduleNextOccurrence(uid string, item TODOItem) error {
+	itemId := item.ItemId
+	series, err := persistence.GetTodoSeriesById(*item.SeriesId)
+	if err != nil {
+		log.Error(fmt.Errorf("unable to retrieve series for TODO item %s: %+v", itemId, err))
+		return err
+	}
+	now := time.Now().UTC()
+	previous := now
+	if item.DueAt != nil {
+		previous = *item.DueAt
+	}
+	// occurrences completed late are rolled forward until the
+	// next occurrence that is not already overdue
+	next := getNextOccurrence(series, previous)
+	for !next.After(now) {
+		next = getNextOccurrence(series, next)
+	}
+	log.Debug(fmt.Sprintf("scheduling next occurrence of series %s at %s", series.SeriesId, next))
+	return persistence.CreateSeriesOccurrence(uid, series, itemId, next)
+}
+
+// function used to skip the current occurrence of a series. the
+// due date of the open occurrence is rolled forward to the next
+// occurrence and the skipped date is recorded on the series
+func skipSeriesOccurrence(uid string, seriesId uuid.UUID) (time.Time, error) {
+	series, err := getTodoSeries(uid, seriesId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if series.OpenItemId == nil || series.NextDue == nil {
+		return time.Time{}, ErrNoOpenOccurrence
+	}
+
+	skipped := series.NextDue.UTC().Format(seriesDateFormat)
+	series.SkippedDates = append(series.SkippedDates, skipped)
+	next := getNextOccurrence(series, *series.NextDue)
+	log.Debug(fmt.Sprintf("skipping occurrence %s of series %s. next occurrence at %s", skipped, seriesId, next))
+	if err := persistence.SkipSeriesOccurrence(uid, seriesId, skipped, next); err != nil {
+		return time.Time{}, err
+	}
+	return next, nil
+}
+
+// function used to retrieve a TODO series owned by a given user
+func getTodoSeries(uid string, seriesId uuid.UUID) (TodoSeries, error) {
+	series, err := persistence.GetTodoSeriesById(seriesId)
+	if err != nil {
+		return TodoSeries{}, err
+	}
+	if series.Owner != uid {
+		return TodoSeries{}, ErrSeriesNotFound
+	}
+	return series, nil
+}
@@ -0,0 +1,209 @@
+package todo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+// define fields time reports can be grouped by
+var timeReportGroups = []string{"project", "tag", "day"}
+
+// define columns of time entry CSV exports
+var timeEntryCSVHeader = []string{"entry_id", "item_id", "item_title", "project_id", "project_name",
+	"tags", "started", "stopped", "duration_seconds", "manual", "note"}
+
+// struct used to return the time spent by a user within a given
+// window, grouped by project, tag or day
+type TimeReport struct {
+	Group string          `json:"group"`
+	From  *time.Time      `json:"from"`
+	To    *time.Time      `json:"to"`
+	Total int64           `json:"total"`
+	Rows  []TimeReportRow `json:"rows"`
+}
+
+// struct used to store the time spent for a single group of a
+// report. time is given in seconds. entries with multiple tags
+// count towards each of their tags
+type TimeReportRow struct {
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Seconds int64  `json:"seconds"`
+	Entries int    `json:"entries"`
+}
+
+// function used to evaluate the end of a time entry. running
+// timers are evaluated up to the given reference time
+func getTimeEntryEnd(entry TimeEntry, now time.Time) time.Time {
+	if entry.Stopped != nil {
+		return *entry.Stopped
+	}
+	return now
+}
+
+// function used to validate manual time entries. manual entries
+// must have a stop time after their start time that is not in
+// the future
+func validateTimeEntry(entry TimeEntry, now time.Time) error {
+	if entry.Stopped == nil || !entry.Stopped.After(entry.Started) || entry.Stopped.After(now) {
+		return ErrInvalidTimeEntry
+	}
+	return nil
+}
+
+// function used to retrieve the location of a given user. users
+// with invalid timezones are assigned UTC
+func getUserLocation(uid string) *time.Location {
+	timezone, err := persistence.GetUserTimezone(uid)
+	if err != nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Warn(fmt.Sprintf("invalid timezone %s for user %s: defaulting to UTC", timezone, uid))
+		return time.UTC
+	}
+	return loc
+}
+
+// function used to split a time entry into the (local) days it
+// spans. the number of seconds spent on each day is returned
+func splitTimeEntryByDay(entry TimeEntry, loc *time.Location, now time.Time) map[string]int64 {
+	days := map[string]int64{}
+	start, end := entry.Started.In(loc), getTimeEntryEnd(entry, now).In(loc)
+	for start.Before(end) {
+		year, month, day := start.Date()
+		midnight := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		if midnight.After(end) {
+			midnight = end
+		}
+		days[start.Format(seriesDateFormat)] += int64(midnight.Sub(start).Seconds())
+		start = midnight
+	}
+	return days
+}
+
+// function used to generate a time report for a given user. entries
+// started within the given window are grouped by project, tag or
+// (local) day. groups are ordered by the time spent on them
+func getTimeReport(uid string, from, to *time.Time, group string) (TimeReport, error) {
+	if !utils.StringSliceContains(timeReportGroups, group) {
+		return TimeReport{}, ErrInvalidTimeReport
+	}
+	entries, err := persistence.GetTimeEntries(uid, TimeEntryFilter{From: from, To: to})
+	if err != nil {
+		return TimeReport{}, err
+	}
+
+	now := time.Now().UTC()
+	loc := getUserLocation(uid)
+	rows := map[string]*TimeReportRow{}
+	add := func(key, label string, seconds int64) {
+		if _, ok := rows[key]; !ok {
+			rows[key] = &TimeReportRow{Key: key, Label: label}
+		}
+		rows[key].Seconds += seconds
+		rows[key].Entries++
+	}
+
+	report := TimeReport{Group: group, From: from, To: to, Rows: []TimeReportRow{}}
+	for _, entry := range entries {
+		report.Total += entry.Duration
+		switch group {
+		case "project":
+			if entry.ProjectId == nil {
+				add("none", "No project", entry.Duration)
+			} else {
+				add(entry.ProjectId.String(), *entry.ProjectName, entry.Duration)
+			}
+		case "tag":
+			if len(entry.Tags) == 0 {
+				add("none", "Untagged", entry.Duration)
+			}
+			for _, tag := range entry.Tags {
+				add(tag, tag, entry.Duration)
+			}
+		case "day":
+			for day, seconds := range splitTimeEntryByDay(entry, loc, now) {
+				add(day, day, seconds)
+			}
+		}
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if group == "day" {
+			return report.Rows[i].Key < report.Rows[j].Key
+		}
+		if report.Rows[i].Seconds != report.Rows[j].Seconds {
+			return report.Rows[i].Seconds > report.Rows[j].Seconds
+		}
+		return report.Rows[i].Key < report.Rows[j].Key
+	})
+	return report, nil
+}
+
+// function used to escape a CSV cell that would otherwise be
+// interpreted as a formula when opened in a spreadsheet
+func escapeCSVCell(value string) string {
+	if len(value) > 0 && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// function used to write time entries as CSV. running timers are
+// exported without a stop time and user-supplied text is escaped
+// so that it cannot be evaluated as a spreadsheet formula
+func writeTimeEntriesCSV(w io.Writer, entries []TimeEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(timeEntryCSVHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		projectId, projectName, stopped := "", "", ""
+		if entry.ProjectId != nil {
+			projectId, projectName = entry.ProjectId.String(), *entry.ProjectName
+		}
+		if entry.Stopped != nil {
+			stopped = entry.Stopped.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			entry.EntryId.String(),
+			entry.ItemId.String(),
+			escapeCSVCell(entry.ItemTitle),
+			projectId,
+			escapeCSVCell(projectName),
+			escapeCSVCell(strings.Join(entry.Tags, ";")),
+			entry.Started.UTC().Format(time.RFC3339),
+			stopped,
+			strconv.FormatInt(entry.Duration, 10),
+			strconv.FormatBool(entry.Manual),
+			escapeCSVCell(entry.Note),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// function used to log a manual time entry on a TODO item
+func logTimeEntry(uid string, itemId uuid.UUID, entry TimeEntry) (uuid.UUID, error) {
+	if err := validateTimeEntry(entry, time.Now().UTC()); err != nil {
+		return uuid.Nil, err
+	}
+	return persistence.CreateTimeEntry(uid, itemId, entry)
+}
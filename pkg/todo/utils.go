@@ -0,0 +1,110 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+// define valid priorities for TODO items in ascending
+// order along with the priority assigned by default
+var todoPriorities = []string{"low", "medium", "high", "urgent"}
+
+const defaultTodoPriority = "medium"
+
+// define roles users can hold for shared projects and items
+// in ascending order of permissions. owners cannot be added as
+// collaborators
+const (
+	roleViewer = "viewer"
+	roleEditor = "editor"
+	roleOwner  = "owner"
+)
+
+var collaboratorRoles = []string{roleViewer, roleEditor, roleOwner}
+
+// function used to determine if a role grants
+// at least the permissions of the required role
+func roleAllows(role, required string) bool {
+	rank := func(r string) int {
+		for i, v := range collaboratorRoles {
+			if v == r {
+				return i
+			}
+		}
+		return -1
+	}
+	return rank(role) >= 0 && rank(role) >= rank(required)
+}
+
+// function used to validate the role given to a collaborator
+func validateCollaboratorRole(role string) error {
+	if role != roleViewer && role != roleEditor {
+		return ErrInvalidCollaboratorRole
+	}
+	return nil
+}
+
+// function used to validate new TODO items. items without
+// a priority are assigned the default priority and tag names
+// are normalized
+func validateTodoItem(item *TODOItem) error {
+	if len(item.ItemTitle) == 0 {
+		return ErrInvalidTODOTitle
+	}
+	if len(item.Priority) == 0 {
+		item.Priority = defaultTodoPriority
+	}
+	if !utils.StringSliceContains(todoPriorities, item.Priority) {
+		return ErrInvalidTODOPriority
+	}
+	if err := validateTodoMetadata(item.Metadata); err != nil {
+		return err
+	}
+	tags, err := normalizeTagNames(item.Tags)
+	if err != nil {
+		return err
+	}
+	item.Tags = tags
+	return nil
+}
+
+// function used to determine if a TODO item is overdue at
+// the given reference time. completed items are never overdue
+func isTodoItemOverdue(item TODOItem, now time.Time) bool {
+	return !item.Completed && item.DueAt != nil && item.DueAt.Before(now)
+}
+
+// function used to determine if a partial update
+// to a TODO item does not set any fields
+func isEmptyTodoItemPatch(patch TODOItemPatch) bool {
+	return patch.ItemTitle == nil && patch.ItemContent == nil && patch.Metadata == nil &&
+		!patch.DueAt.Set && !patch.RemindAt.Set && patch.Priority == nil && patch.Tags == nil
+}
+
+// function used to apply a partial update to a TODO item
+func applyTodoItemPatch(item TODOItem, patch TODOItemPatch) TODOItem {
+	if patch.ItemTitle != nil {
+		item.ItemTitle = *patch.ItemTitle
+	}
+	if patch.ItemContent != nil {
+		item.ItemContent = *patch.ItemContent
+	}
+	if patch.Metadata != nil {
+		item.Metadata = patch.Metadata
+	}
+	// due and reminder dates are cleared if explicitly set to null
+	if patch.DueAt.Set {
+		item.DueAt = patch.DueAt.Value
+	}
+	if patch.RemindAt.Set {
+		item.RemindAt = patch.RemindAt.Value
+	}
+	if patch.Priority != nil {
+		item.Priority = *patch.Priority
+	}
+	// tags are only replaced if given. the tags of the current
+	// item include tags of other users and are never written back
+	item.Tags = patch.Tags
+	return item
+}
@@ -0,0 +1,131 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+// struct used to return the items of a project grouped by
+// their workflow state. warnings are generated for all states
+// that hold more items than allowed by their WIP limit
+type Board struct {
+	ProjectId uuid.UUID     `json:"project_id"`
+	Columns   []BoardColumn `json:"columns"`
+	Warnings  []string      `json:"warnings"`
+}
+
+type BoardColumn struct {
+	State       string     `json:"state"`
+	WIPLimit    int64      `json:"wip_limit"`
+	Done        bool       `json:"done"`
+	Count       int        `json:"count"`
+	WIPExceeded bool       `json:"wip_exceeded"`
+	Items       []TODOItem `json:"items"`
+}
+
+// function used to validate a project workflow. workflows must have an
+// initial (first) state and at least one done state. if no transitions
+// are given, items can be moved between neighbouring states
+func validateWorkflow(workflow *Workflow) error {
+	if len(workflow.States) < 2 || workflow.States[0].Done {
+		return ErrInvalidWorkflow
+	}
+	names := []string{}
+	done := false
+	for i := range workflow.States {
+		state := &workflow.States[i]
+		state.Name = strings.TrimSpace(state.Name)
+		if len(state.Name) == 0 || utils.StringSliceContains(names, state.Name) || state.WIPLimit < 0 {
+			return ErrInvalidWorkflow
+		}
+		state.Position = int64(i)
+		names = append(names, state.Name)
+		done = done || state.Done
+	}
+	if !done {
+		return ErrInvalidWorkflow
+	}
+
+	if len(workflow.Transitions) == 0 {
+		for i := 1; i < len(names); i++ {
+			workflow.Transitions = append(workflow.Transitions,
+				WorkflowTransition{From: names[i-1], To: names[i]},
+				WorkflowTransition{From: names[i], To: names[i-1]})
+		}
+		return nil
+	}
+	for i := range workflow.Transitions {
+		transition := &workflow.Transitions[i]
+		transition.From, transition.To = strings.TrimSpace(transition.From), strings.TrimSpace(transition.To)
+		if !utils.StringSliceContains(names, transition.From) || !utils.StringSliceContains(names, transition.To) ||
+			transition.From == transition.To {
+			return ErrInvalidWorkflow
+		}
+	}
+	return nil
+}
+
+// function used to generate the board of a project. items are
+// grouped into columns in the order of the workflow states
+func getProjectBoard(uid string, projectId uuid.UUID) (Board, error) {
+	workflow, err := persistence.GetProjectWorkflow(uid, projectId)
+	if err != nil {
+		return Board{}, err
+	}
+	page, err := persistence.GetTodoItems(uid, TODOItemFilter{Project: projectId.String()})
+	if err != nil {
+		return Board{}, err
+	}
+
+	board := Board{ProjectId: projectId, Columns: []BoardColumn{}, Warnings: []string{}}
+	columns := map[string]int{}
+	for _, state := range workflow.States {
+		columns[state.Name] = len(board.Columns)
+		board.Columns = append(board.Columns, BoardColumn{State: state.Name,
+			WIPLimit: state.WIPLimit, Done: state.Done, Items: []TODOItem{}})
+	}
+	for _, item := range page.Items {
+		if item.State == nil {
+			continue
+		}
+		if i, ok := columns[*item.State]; ok {
+			board.Columns[i].Items = append(board.Columns[i].Items, item)
+		}
+	}
+	for i := range board.Columns {
+		column := &board.Columns[i]
+		column.Count = len(column.Items)
+		if column.WIPLimit > 0 && int64(column.Count) > column.WIPLimit {
+			column.WIPExceeded = true
+			board.Warnings = append(board.Warnings, fmt.Sprintf("state %s exceeds WIP limit (%d/%d)",
+				column.State, column.Count, column.WIPLimit))
+		}
+	}
+	return board, nil
+}
+
+// function used to move a TODO item into another workflow state.
+// moving an occurrence of a series into a done state creates the
+// next occurrence in the same way as completing the item
+func transitionTodoItem(uid string, itemId uuid.UUID, state string) (TodoStateChange, error) {
+	item, err := persistence.GetTodoItem(uid, itemId)
+	if err != nil {
+		return TodoStateChange{}, err
+	}
+	change, err := persistence.TransitionTodoItem(uid, itemId, state)
+	if err != nil {
+		return TodoStateChange{}, err
+	}
+	if item.Completed || item.SeriesId == nil {
+		return change, nil
+	}
+	updated, err := persistence.GetTodoItem(uid, itemId)
+	if err != nil || !updated.Completed {
+		return change, err
+	}
+	return change, scheduleNextOccurrence(uid, item)
+}
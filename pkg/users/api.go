@@ -2,6 +2,7 @@ package users
 
 import (
     "fmt"
+    "time"
     "net/http"
 
     "github.com/gin-gonic/gin"
@@ -19,6 +20,7 @@ func NewUsersAPI() *gin.Engine {
 
     router.GET("/users/health_check", healthCheckHandler)
     router.GET("/users/user", getUserHandler)
+    router.PUT("/users/timezone", setUserTimezoneHandler)
     router.GET("/users/details/:uid", AdminProtected(), getUserDetailsHandler)
     router.POST("/users/new", AdminProtected(), createUserHandler)
     return router
@@ -128,4 +130,45 @@ func createUserHandler(ctx *gin.Context) {
     }
     ctx.JSON(http.StatusCreated, gin.H{"http_code": http.StatusCreated,
         "success": true, "message": "Successfully created user"})
-}
\ No newline at end of file
+}
+
+// API handler used to set the timezone of a user. timezones
+// must be valid IANA timezones (e.g. Europe/Berlin)
+func setUserTimezoneHandler(ctx *gin.Context) {
+    log.Info("received request to set user timezone")
+    var request struct {
+        Timezone string `json:"timezone" binding:"required"`
+    }
+    if err := ctx.ShouldBind(&request); err != nil {
+        log.Error(fmt.Errorf("received invalid request: %+v", err))
+        ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+            "http_code": http.StatusBadRequest, "success": false,
+            "message": "Invalid request body"})
+        return
+    }
+    if _, err := time.LoadLocation(request.Timezone); err != nil {
+        log.Error(fmt.Sprintf("received invalid timezone %s", request.Timezone))
+        ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+            "http_code": http.StatusBadRequest, "success": false,
+            "message": "Invalid timezone"})
+        return
+    }
+
+    // retrieve user ID from context
+    uid := ctx.MustGet("uid").(string)
+    if err := persistence.SetUserTimezone(uid, request.Timezone); err != nil {
+        switch err {
+        case ErrUserDoesNotExist:
+            ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+                "http_code": http.StatusNotFound, "success": false,
+                "message": "Cannot find user"})
+        default:
+            ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+                "http_code": http.StatusInternalServerError, "success": false,
+                "message": "Internal server error"})
+        }
+        return
+    }
+    ctx.JSON(http.StatusOK, gin.H{"http_code": http.StatusOK,
+        "success": true, "message": "Successfully updated timezone"})
+}
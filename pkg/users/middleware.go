@@ -14,12 +14,13 @@ func AdminProtected() gin.HandlerFunc {
     return func(ctx *gin.Context) {
         // get user ID from header and get admin access
         uid := ctx.Request.Header.Get("X-Authenticated-Userid")
-        admin, err := isAdminUser(uid)
+        admin, err := persistence.IsAdminUser(uid)
         if err != nil {
             log.Error(fmt.Errorf("unable to check admin status for user: %+v", err))
             ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
                 "http_code": http.StatusInternalServerError, "success": false, 
                 "message": "Internal server error"})
+            return
         }
         
         // return 403 if user does not have admin rights
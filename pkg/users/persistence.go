@@ -147,3 +147,36 @@ func(db *GraphPersistence) AddUser(user User) error {
     }
     return nil
 }
+
+// function used to set the timezone of a user. the timezone is
+// read by other modules using the base graph accessor
+func(db *GraphPersistence) SetUserTimezone(uid, timezone string) error {
+    log.Debug(fmt.Sprintf("setting timezone %s for user %s...", timezone, uid))
+    // create new persitence session for graph and defer closing
+    session := db.NewSession()
+    defer session.Close()
+    // generate config metadata for query
+    cfg := map[string]interface{}{
+        "uid": uid,
+        "timezone": timezone,
+    }
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `MATCH (n:User {uid: $uid})
+        SET n.timezone = $timezone
+        RETURN n.uid`
+        results, err := tx.Run(query, cfg)
+        if err != nil {
+            return nil, err
+        }
+        if _, err := neo4j.Single(results, err); err != nil {
+            return nil, ErrUserDoesNotExist
+        }
+        return nil, nil
+    }
+    _, err := session.WriteTransaction(handler)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to set user timezone: %+v", err))
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+    "fmt"
+    "time"
+    "errors"
+    "io/ioutil"
+
+    "github.com/google/uuid"
+    log "github.com/sirupsen/logrus"
+
+    "github.com/PSauerborn/lifelink/pkg/utils"
+)
+
+var (
+    // define custom errors
+    ErrTodoItemDoesNotExist = errors.New("TODO item does not exist")
+    ErrTodoPermissionDenied = errors.New("Insufficient permissions for TODO item")
+    ErrTodoItemPromoted     = errors.New("TODO item has already been promoted")
+)
+
+const (
+    // define timeout used for requests to the TODO API
+    todoAPITimeout = time.Second * 10
+)
+
+type TodoAPIAccessor struct {
+    *utils.BaseAPIAccessor
+}
+
+// function to generate new API accessor for TODO API
+func NewTodoApiAccessor(host, protocol string, port *int) *TodoAPIAccessor {
+    baseAccessor := utils.BaseAPIAccessor{
+        Host: host,
+        Port: port,
+        Protocol: protocol,
+        Timeout: todoAPITimeout,
+    }
+    return &TodoAPIAccessor{
+        &baseAccessor,
+    }
+}
+
+// function to generate new API accessor for TODO API
+func NewTodoApiAccessorFromConfig(config utils.APIDependencyConfig) *TodoAPIAccessor {
+    baseAccessor := utils.NewAPIAccessorFromConfig(config)
+    baseAccessor.Timeout = todoAPITimeout
+    return &TodoAPIAccessor{
+        baseAccessor,
+    }
+}
+
+// API function used to check that a TODO item exists
+// and can be read by the given user
+func(accessor *TodoAPIAccessor) CheckTodoItem(uid string, itemId uuid.UUID) error {
+    log.Debug(fmt.Sprintf("checking access to TODO item %s for user %s", itemId, uid))
+    url := accessor.FormatURL(fmt.Sprintf("todo/item/%s", itemId))
+    return accessor.executeTodoRequest("GET", url, uid)
+}
+
+// API function used to complete a TODO item on behalf of
+// the given user. items are completed by the TODO API, which
+// checks permissions and records the change of state
+func(accessor *TodoAPIAccessor) CompleteTodoItem(uid string, itemId uuid.UUID) error {
+    log.Debug(fmt.Sprintf("completing TODO item %s for user %s", itemId, uid))
+    url := accessor.FormatURL(fmt.Sprintf("todo/complete/%s", itemId))
+    return accessor.executeTodoRequest("PATCH", url, uid)
+}
+
+// API function used to mark a TODO item as promoted into a
+// habit. items can only be promoted once
+func(accessor *TodoAPIAccessor) PromoteTodoItem(uid string, itemId uuid.UUID) error {
+    log.Debug(fmt.Sprintf("promoting TODO item %s for user %s", itemId, uid))
+    url := accessor.FormatURL(fmt.Sprintf("todo/item/%s/promote", itemId))
+    return accessor.executeTodoRequest("PATCH", url, uid)
+}
+
+// API function used to release the promotion of a TODO item
+func(accessor *TodoAPIAccessor) ReleaseTodoItemPromotion(uid string, itemId uuid.UUID) error {
+    log.Debug(fmt.Sprintf("releasing promotion of TODO item %s for user %s", itemId, uid))
+    url := accessor.FormatURL(fmt.Sprintf("todo/item/%s/promote", itemId))
+    return accessor.executeTodoRequest("DELETE", url, uid)
+}
+
+// function used to execute a request against the TODO API
+// and to convert the response status code into an error
+func(accessor *TodoAPIAccessor) executeTodoRequest(method, url, uid string) error {
+    headers := map[string]string{"X-Authenticated-Userid": uid}
+    req, err := accessor.NewJSONRequest(method, url, nil, headers)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to generate new HTTP request: %+v", err))
+        return err
+    }
+    // execute HTTP request
+    resp, err := accessor.ExecuteRequest(req)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to execute API request: %+v", err))
+        return err
+    }
+    defer resp.Body.Close()
+
+    switch resp.StatusCode {
+    case 200:
+        return nil
+    case 403:
+        log.Error("cannot access TODO item from API: insufficient permissions")
+        return ErrTodoPermissionDenied
+    case 404:
+        log.Error("cannot access TODO item from API: item does not exist")
+        return ErrTodoItemDoesNotExist
+    case 409:
+        log.Error("cannot promote TODO item from API: item already promoted")
+        return ErrTodoItemPromoted
+    default:
+        // parse response body and log
+        responseBody, _ := ioutil.ReadAll(resp.Body)
+        log.Error(fmt.Errorf("received invalid response from API with status code %d: %+v",
+            resp.StatusCode, string(responseBody)))
+        return utils.ErrInvalidAPIResponse
+    }
+}
@@ -30,6 +30,9 @@ type BaseAPIAccessor struct {
     Host string
     Port *int
     Protocol string
+    // timeout applied to requests. requests
+    // never time out if no timeout is set
+    Timeout time.Duration
 }
 
 func NewAPIAccessorFromConfig(config APIDependencyConfig) *BaseAPIAccessor {
@@ -45,7 +48,7 @@ func(accessor *BaseAPIAccessor) ExecuteRequest(request *http.Request) (*http.Res
     // generate new HTTP client and execute request
     start := time.Now()
     log.Debug(fmt.Sprintf("making request to url %s...", request.URL))
-    client := &http.Client{}
+    client := &http.Client{Timeout: accessor.Timeout}
     resp, err := client.Do(request)
     if err != nil {
         log.Error(fmt.Errorf("unable to execute HTTP request: %+v", err))
@@ -0,0 +1,144 @@
+package utils
+
+import (
+    "io"
+    "os"
+    "fmt"
+    "errors"
+    "regexp"
+    "io/ioutil"
+    "crypto/sha256"
+    "path/filepath"
+    "encoding/hex"
+
+    log "github.com/sirupsen/logrus"
+)
+
+var (
+    // define custom errors
+    ErrInvalidBlobStore = errors.New("Invalid blob store")
+    ErrBlobNotFound     = errors.New("Cannot find specified blob")
+    ErrInvalidDigest    = errors.New("Invalid blob digest")
+)
+
+// define pattern used to validate blob digests (hex encoded SHA-256)
+var digestPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// interface used to store binary content (attachments etc). blobs
+// are content-addressed and identified by the SHA-256 digest of
+// their content, so identical content is only stored once
+type BlobStore interface {
+    Put(content io.Reader) (string, int64, error)
+    Get(digest string) (io.ReadCloser, error)
+    Delete(digest string) error
+}
+
+// function used to compute the digest and size of content
+// using the same digest used to identify blobs in blob stores
+func BlobDigest(content io.Reader) (string, int64, error) {
+    hash := sha256.New()
+    size, err := io.Copy(hash, content)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to compute blob digest: %+v", err))
+        return "", 0, err
+    }
+    return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// function used to generate a new blob store for a given backend.
+// the target is interpreted based on the backend (root directory
+// for local blob stores)
+func NewBlobStore(backend, target string) (BlobStore, error) {
+    switch backend {
+    case "local":
+        return NewLocalBlobStore(target)
+    default:
+        log.Error(fmt.Sprintf("received invalid blob store backend %s", backend))
+        return nil, ErrInvalidBlobStore
+    }
+}
+
+// blob store used to store blobs on the local filesystem. blobs
+// are stored in subdirectories named after the first two characters
+// of their digest to keep directory sizes manageable
+type LocalBlobStore struct {
+    Root string
+}
+
+// function used to generate new local blob store
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+    if err := os.MkdirAll(root, 0755); err != nil {
+        log.Error(fmt.Errorf("unable to create blob store directory: %+v", err))
+        return nil, err
+    }
+    return &LocalBlobStore{Root: root}, nil
+}
+
+// function used to generate the path of a blob with a given digest
+func(store *LocalBlobStore) blobPath(digest string) (string, error) {
+    if !digestPattern.MatchString(digest) {
+        return "", ErrInvalidDigest
+    }
+    return filepath.Join(store.Root, digest[:2], digest), nil
+}
+
+// function used to store content in blob store. content is first
+// written to a temporary file and moved into place once its digest
+// has been computed. the digest and size of the content are returned
+func(store *LocalBlobStore) Put(content io.Reader) (string, int64, error) {
+    file, err := ioutil.TempFile(store.Root, ".upload-")
+    if err != nil {
+        log.Error(fmt.Errorf("unable to create temporary blob file: %+v", err))
+        return "", 0, err
+    }
+    defer os.Remove(file.Name())
+
+    hash := sha256.New()
+    size, err := io.Copy(io.MultiWriter(file, hash), content)
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    if err != nil {
+        log.Error(fmt.Errorf("unable to write blob content: %+v", err))
+        return "", 0, err
+    }
+
+    digest := hex.EncodeToString(hash.Sum(nil))
+    path, _ := store.blobPath(digest)
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        log.Error(fmt.Errorf("unable to create blob directory: %+v", err))
+        return "", 0, err
+    }
+    if err := os.Rename(file.Name(), path); err != nil {
+        log.Error(fmt.Errorf("unable to move blob into place: %+v", err))
+        return "", 0, err
+    }
+    return digest, size, nil
+}
+
+// function used to retrieve content of blob with given digest
+func(store *LocalBlobStore) Get(digest string) (io.ReadCloser, error) {
+    path, err := store.blobPath(digest)
+    if err != nil {
+        return nil, err
+    }
+    file, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return nil, ErrBlobNotFound
+    }
+    return file, err
+}
+
+// function used to delete blob with given digest. deleting
+// a blob that does not exist is not considered an error
+func(store *LocalBlobStore) Delete(digest string) error {
+    path, err := store.blobPath(digest)
+    if err != nil {
+        return err
+    }
+    if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+        log.Error(fmt.Errorf("unable to delete blob %s: %+v", digest, err))
+        return err
+    }
+    return nil
+}
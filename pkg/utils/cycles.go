@@ -0,0 +1,17 @@
+package utils
+
+// define valid days in weekly cycles. cycles are
+// stored as comma-separated lists of these days
+var ValidCycles = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// define reverse mappings for cycles to
+// convert from integer days to strings
+var ReverseCycleMappings = map[int]string{
+    0: "sun",
+    1: "mon",
+    2: "tue",
+    3: "wed",
+    4: "thu",
+    5: "fri",
+    6: "sat",
+}
@@ -42,4 +42,60 @@ func(accessor *BaseGraphAccessor) NewSession() neo4j.Session {
     session := accessor.Driver.NewSession(neo4j.SessionConfig{
         AccessMode: neo4j.AccessModeWrite})
     return session
-}
\ No newline at end of file
+}
+
+// function used to retrieve the timezone set for a user. users
+// without a timezone are treated as being in UTC
+func(accessor *BaseGraphAccessor) GetUserTimezone(uid string) (string, error) {
+    log.Debug(fmt.Sprintf("retrieving timezone for user %s...", uid))
+    // create new persistence session for graph and defer closing
+    session := accessor.NewSession()
+    defer session.Close()
+    // generate config metadata for query
+    cfg := map[string]interface{}{
+        "uid": uid,
+    }
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `OPTIONAL MATCH (u:User {uid: $uid})
+        RETURN coalesce(u.timezone, 'UTC')`
+        results, err := tx.Run(query, cfg)
+        if err != nil {
+            return nil, err
+        }
+        return neo4j.Single(results, err)
+    }
+    node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+    if err != nil {
+        log.Error(fmt.Errorf("unable to retrieve user timezone: %+v", err))
+        return "UTC", err
+    }
+    return node.Values[0].(string), nil
+}
+
+// function used to determine if a given user has admin access.
+// users that do not exist are treated as non-admin users
+func(accessor *BaseGraphAccessor) IsAdminUser(uid string) (bool, error) {
+    log.Debug(fmt.Sprintf("checking admin access for user %s...", uid))
+    // create new persistence session for graph and defer closing
+    session := accessor.NewSession()
+    defer session.Close()
+    // generate config metadata for query
+    cfg := map[string]interface{}{
+        "uid": uid,
+    }
+    handler := func(tx neo4j.Transaction) (interface{}, error) {
+        query := `OPTIONAL MATCH (u:User {uid: $uid})
+        RETURN coalesce(u.admin, false)`
+        results, err := tx.Run(query, cfg)
+        if err != nil {
+            return nil, err
+        }
+        return neo4j.Single(results, err)
+    }
+    node, err := neo4j.AsRecord(session.ReadTransaction(handler))
+    if err != nil {
+        log.Error(fmt.Errorf("unable to check admin access: %+v", err))
+        return false, err
+    }
+    return node.Values[0].(bool), nil
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+    "os"
+    "fmt"
+    "sync"
+    "time"
+    "bytes"
+    "errors"
+    "io/ioutil"
+    "encoding/json"
+
+    log "github.com/sirupsen/logrus"
+)
+
+var (
+    // define custom errors
+    ErrInvalidNotificationSink   = errors.New("Invalid notification sink")
+    ErrInvalidNotificationTarget = errors.New("Invalid notification target")
+)
+
+const (
+    // define timeout used when delivering notifications to
+    // webhooks so that slow sinks cannot block reminders
+    webhookTimeout = time.Second * 10
+)
+
+// struct used to store notifications sent to users
+// by background workers (reminders etc)
+type Notification struct {
+    Uid     string                 `json:"uid"`
+    Source  string                 `json:"source"`
+    Subject string                 `json:"subject"`
+    Message string                 `json:"message"`
+    Data    map[string]interface{} `json:"data"`
+    Created time.Time              `json:"created"`
+}
+
+// interface used to deliver notifications to a sink
+type Notifier interface {
+    Notify(notification Notification) error
+}
+
+// function used to generate a new notifier for a given sink.
+// the target is interpreted based on the sink (file path for
+// file sinks, URL for webhook sinks) and ignored for log sinks
+func NewNotifier(sink, target string) (Notifier, error) {
+    switch sink {
+    case "log":
+        return &LogNotifier{}, nil
+    case "file":
+        return NewFileNotifier(target), nil
+    case "webhook":
+        if len(target) == 0 {
+            log.Error("received empty target URL for webhook notification sink")
+            return nil, ErrInvalidNotificationTarget
+        }
+        return NewWebhookNotifier(target), nil
+    default:
+        log.Error(fmt.Sprintf("received invalid notification sink %s", sink))
+        return nil, ErrInvalidNotificationSink
+    }
+}
+
+// notifier used to write notifications to application logs
+type LogNotifier struct{}
+
+// function used to write notification to logs
+func(notifier *LogNotifier) Notify(notification Notification) error {
+    log.Info(fmt.Sprintf("notification for user %s: %s - %s", notification.Uid,
+        notification.Subject, notification.Message))
+    return nil
+}
+
+// notifier used to append notifications to a file
+// as newline-delimited JSON
+type FileNotifier struct {
+    Path string
+    lock sync.Mutex
+}
+
+// function used to generate new file notifier
+func NewFileNotifier(path string) *FileNotifier {
+    return &FileNotifier{Path: path}
+}
+
+// function used to append notification to file
+func(notifier *FileNotifier) Notify(notification Notification) error {
+    body, err := json.Marshal(notification)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to serialize notification: %+v", err))
+        return err
+    }
+    notifier.lock.Lock()
+    defer notifier.lock.Unlock()
+
+    file, err := os.OpenFile(notifier.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to open notification file: %+v", err))
+        return err
+    }
+    defer file.Close()
+    _, err = file.Write(append(body, '\n'))
+    return err
+}
+
+// notifier used to POST notifications to a webhook as JSON
+type WebhookNotifier struct {
+    *BaseAPIAccessor
+    URL string
+}
+
+// function used to generate new webhook notifier
+func NewWebhookNotifier(url string) *WebhookNotifier {
+    return &WebhookNotifier{
+        BaseAPIAccessor: &BaseAPIAccessor{Timeout: webhookTimeout},
+        URL: url,
+    }
+}
+
+// function used to send notification to webhook
+func(notifier *WebhookNotifier) Notify(notification Notification) error {
+    body, err := json.Marshal(notification)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to serialize notification: %+v", err))
+        return err
+    }
+    req, err := notifier.NewJSONRequest("POST", notifier.URL, bytes.NewBuffer(body), nil)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to generate new HTTP request: %+v", err))
+        return err
+    }
+    resp, err := notifier.ExecuteRequest(req)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to execute webhook request: %+v", err))
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        responseBody, _ := ioutil.ReadAll(resp.Body)
+        log.Error(fmt.Errorf("received invalid response from webhook with status code %d: %+v",
+            resp.StatusCode, string(responseBody)))
+        return ErrInvalidAPIResponse
+    }
+    return nil
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+    "fmt"
+    "time"
+    "bytes"
+    "errors"
+    "net/url"
+    "net/http"
+    "io/ioutil"
+    "encoding/json"
+
+    log "github.com/sirupsen/logrus"
+)
+
+var (
+    // define custom errors
+    ErrModuleNotRegistered = errors.New("Module is not registered with gateway")
+)
+
+const (
+    // define interval used to retry failed registrations and the
+    // minimum interval between lease renewals
+    registrationRetryInterval = time.Second * 5
+    minimumRenewalInterval    = time.Second
+    // define timeout used for requests to the gateway. timeouts are
+    // reduced to the renewal interval for leases shorter than this
+    registrationTimeout = time.Second * 5
+)
+
+// struct used to advertise a module to the gateway
+type ModuleRegistration struct {
+    ModuleName        string `json:"module_name"`
+    ModuleRedirect    string `json:"module_redirect"`
+    ModuleDescription string `json:"module_description"`
+}
+
+// registrar used to register a module with the gateway admin
+// API and to periodically renew the lease of the module
+type GatewayRegistrar struct {
+    *BaseAPIAccessor
+    AdminURL string
+    Secret   string
+    Module   ModuleRegistration
+}
+
+// function used to generate new gateway registrar. the secret
+// is shared with the gateway and authenticates the module
+func NewGatewayRegistrar(adminURL, secret string, module ModuleRegistration) *GatewayRegistrar {
+    return &GatewayRegistrar{
+        BaseAPIAccessor: &BaseAPIAccessor{Timeout: registrationTimeout},
+        AdminURL: adminURL,
+        Secret: secret,
+        Module: module,
+    }
+}
+
+// function used to send a lease request to the gateway admin API.
+// the duration of the lease granted by the gateway is returned
+func(registrar *GatewayRegistrar) sendLeaseRequest(method, url string, body []byte) (time.Duration, error) {
+    headers := map[string]string{"X-Registration-Secret": registrar.Secret}
+    req, err := registrar.NewJSONRequest(method, url, bytes.NewBuffer(body), headers)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to generate new HTTP request: %+v", err))
+        return 0, err
+    }
+    resp, err := registrar.ExecuteRequest(req)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to execute lease request: %+v", err))
+        return 0, err
+    }
+    defer resp.Body.Close()
+
+    responseBody, _ := ioutil.ReadAll(resp.Body)
+    switch {
+    case resp.StatusCode == http.StatusNotFound:
+        return 0, ErrModuleNotRegistered
+    case resp.StatusCode < 200 || resp.StatusCode > 299:
+        log.Error(fmt.Errorf("received invalid response from gateway with status code %d: %+v",
+            resp.StatusCode, string(responseBody)))
+        return 0, ErrInvalidAPIResponse
+    }
+    var lease struct {
+        LeaseSeconds int `json:"lease_seconds"`
+    }
+    if err := json.Unmarshal(responseBody, &lease); err != nil {
+        log.Error(fmt.Errorf("unable to parse lease response: %+v", err))
+        return 0, ErrInvalidJSONResponse
+    }
+    return time.Duration(lease.LeaseSeconds) * time.Second, nil
+}
+
+// function used to register module with gateway
+func(registrar *GatewayRegistrar) Register() (time.Duration, error) {
+    log.Info(fmt.Sprintf("registering module %s with gateway", registrar.Module.ModuleName))
+    body, err := json.Marshal(registrar.Module)
+    if err != nil {
+        log.Error(fmt.Errorf("unable to serialize module registration: %+v", err))
+        return 0, err
+    }
+    return registrar.sendLeaseRequest("POST", fmt.Sprintf("%s/admin/modules", registrar.AdminURL), body)
+}
+
+// function used to renew the lease of the module
+func(registrar *GatewayRegistrar) Renew() (time.Duration, error) {
+    log.Debug(fmt.Sprintf("renewing lease for module %s", registrar.Module.ModuleName))
+    return registrar.sendLeaseRequest("PUT", fmt.Sprintf("%s/admin/modules/%s/lease",
+        registrar.AdminURL, url.PathEscape(registrar.Module.ModuleName)), nil)
+}
+
+// function used to register module and keep its lease alive. leases
+// are renewed after a third of their duration has passed, and modules
+// are registered again if the gateway no longer knows the module. the
+// function blocks and should be run in a goroutine
+func(registrar *GatewayRegistrar) Run() {
+    registered := false
+    for {
+        var lease time.Duration
+        var err error
+        if registered {
+            if lease, err = registrar.Renew(); err == ErrModuleNotRegistered {
+                log.Warn(fmt.Sprintf("module %s no longer registered with gateway", registrar.Module.ModuleName))
+                registered = false
+            }
+        }
+        if !registered {
+            lease, err = registrar.Register()
+            registered = err == nil
+        }
+
+        wait := lease / 3
+        if err != nil {
+            log.Error(fmt.Errorf("unable to maintain gateway lease: %+v", err))
+            wait = registrationRetryInterval
+        } else if wait < minimumRenewalInterval {
+            wait = minimumRenewalInterval
+        }
+        // requests must time out before the lease expires so
+        // that a hanging gateway cannot delay the next renewal
+        if err == nil && wait < registrar.Timeout {
+            registrar.Timeout = wait
+        }
+        time.Sleep(wait)
+    }
+}
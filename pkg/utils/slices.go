@@ -0,0 +1,12 @@
+package utils
+
+// helper function used to determine if a string
+// slice contains a given element
+func StringSliceContains(slice []string, element string) bool {
+    for _, ele := range slice {
+        if ele == element {
+            return true
+        }
+    }
+    return false
+}